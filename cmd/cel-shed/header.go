@@ -1,21 +1,36 @@
 package main
 
 import (
+	"context"
+	"encoding/hex"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/celestiaorg/celestia-node/header"
 	"github.com/celestiaorg/celestia-node/header/store"
 	"github.com/celestiaorg/celestia-node/nodebuilder"
 	"github.com/celestiaorg/celestia-node/nodebuilder/node"
 )
 
 func init() {
-	headerCmd.AddCommand(headerStoreInit)
+	headerImport.Flags().String(trustedHashFlag, "",
+		"Hex hash of the trusted header in the snapshot to initialize the empty store with")
+	headerVerify.Flags().String(trustedHashFlag, "",
+		"Hex hash of the trusted header to verify the following ones from, defaults to the first one")
+	headerVerify.Flags().Duration(trustingPeriodFlag, header.TrustingPeriod,
+		"Period through which a header's validator set is trusted to verify non-adjacent headers")
+	headerCmd.AddCommand(headerStoreInit, headerExport, headerImport, headerVerify)
 }
 
+const (
+	trustedHashFlag    = "trusted-hash"
+	trustingPeriodFlag = "trusting-period"
+)
+
 var headerCmd = &cobra.Command{
 	Use:   "header [subcommand]",
 	Short: "Collection of header module related utilities",
@@ -67,3 +82,207 @@ Custom store path is not supported yet.`,
 		return hstore.Init(cmd.Context(), newHead)
 	},
 }
+
+var headerExport = &cobra.Command{
+	Use: "export [node-type] [network] [from] [to] [file]",
+	Short: `Export headers of the given height range [from:to] from the header store into the snapshot file.
+Requires the node being stopped. Custom store path is not supported yet.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 5 {
+			return fmt.Errorf("not enough arguments")
+		}
+
+		from, err := strconv.ParseUint(args[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid from height: %w", err)
+		}
+
+		to, err := strconv.ParseUint(args[3], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid to height: %w", err)
+		}
+
+		hstore, closeStore, err := openHeaderStore(cmd.Context(), args[0], args[1])
+		if err != nil {
+			return err
+		}
+		defer closeStore() //nolint: errcheck
+
+		file, err := os.Create(args[4])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		n, err := store.Export(cmd.Context(), hstore, file, from, to)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Exported %d headers to %s\n", n, args[4])
+		return file.Close()
+	},
+}
+
+var headerImport = &cobra.Command{
+	Use: "import [node-type] [network] [file]",
+	Short: `Import headers from the snapshot file into the header store, verifying them against its head.
+Requires the node being stopped. Custom store path is not supported yet.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 3 {
+			return fmt.Errorf("not enough arguments")
+		}
+
+		trustedHash, err := hex.DecodeString(cmd.Flag(trustedHashFlag).Value.String())
+		if err != nil {
+			return fmt.Errorf("invalid trusted hash: %w", err)
+		}
+
+		file, err := os.Open(args[2])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		hstore, closeStore, err := openHeaderStore(cmd.Context(), args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		n, err := store.Import(cmd.Context(), hstore, file, trustedHash)
+		// imported headers are flushed on close
+		if cerr := closeStore(); cerr != nil && err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("imported %d headers: %w", n, err)
+		}
+
+		fmt.Printf("Imported %d headers from %s\n", n, args[2])
+		return nil
+	},
+}
+
+var headerVerify = &cobra.Command{
+	Use: "verify [file] | verify [node-type] [network]",
+	Short: `Verify the header chain of the snapshot file or of the header store of the stopped node
+without running a node. Reports the first header failing verification.
+The header store is not migrated, but opening it still records the schema version of an empty store
+and the tail of a store written before the tail was tracked. Custom store path is not supported yet.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var src header.Source
+		switch len(args) {
+		case 1:
+			file, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			src = store.NewSnapshotSource(file)
+		case 2:
+			// the audited store is not migrated, as its headers are readable before migrations,
+			// though opening it records the schema version and the tail, if they are missing
+			hstore, closeStore, err := openHeaderStore(cmd.Context(), args[0], args[1],
+				nodebuilder.WithMigrationDryRun())
+			if err != nil {
+				return err
+			}
+			defer closeStore() //nolint: errcheck
+
+			tail, err := hstore.Tail(cmd.Context())
+			if err != nil {
+				return err
+			}
+			src = header.NewRangeSource(hstore, uint64(tail.Height), hstore.Height(), 512)
+		default:
+			return fmt.Errorf("not enough arguments")
+		}
+
+		trustedHash, err := hex.DecodeString(cmd.Flag(trustedHashFlag).Value.String())
+		if err != nil {
+			return fmt.Errorf("invalid trusted hash: %w", err)
+		}
+
+		trustingPeriod, err := cmd.Flags().GetDuration(trustingPeriodFlag)
+		if err != nil {
+			return err
+		}
+
+		var trusted *header.ExtendedHeader
+		if len(trustedHash) != 0 {
+			trusted, err = header.FindTrusted(cmd.Context(), src, trustedHash)
+		} else {
+			trusted, err = src.Next(cmd.Context())
+		}
+		if err != nil {
+			return fmt.Errorf("getting trusted header: %w", err)
+		}
+		fmt.Printf("Trusting header %s at height %d\n", trusted.Hash(), trusted.Height)
+
+		v := header.NewVerifier(trusted, header.WithTrustingPeriod(trustingPeriod))
+		n, err := v.VerifyAll(cmd.Context(), src)
+		if err != nil {
+			return fmt.Errorf("verified %d headers up to height %d: %w", n, v.Trusted().Height, err)
+		}
+
+		fmt.Printf("Verified %d headers up to height %d\n", n, v.Trusted().Height)
+		return nil
+	},
+}
+
+// openHeaderStore opens and starts the header store of the stopped node.
+// The returned func stops the header store and closes the node store.
+func openHeaderStore(
+	ctx context.Context,
+	nodeType, network string,
+	opts ...nodebuilder.MigrationOption,
+) (header.Store, func() error, error) {
+	tp := node.ParseType(nodeType)
+	if !tp.IsValid() {
+		return nil, nil, fmt.Errorf("invalid node-type")
+	}
+
+	s, err := nodebuilder.OpenStore(fmt.Sprintf("~/.celestia-%s-%s", strings.ToLower(tp.String()),
+		strings.ToLower(network)), opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ds, err := s.Datastore()
+	if err != nil {
+		s.Close() //nolint: errcheck
+		return nil, nil, err
+	}
+
+	hstore, err := store.NewStore(ds)
+	if err != nil {
+		s.Close() //nolint: errcheck
+		return nil, nil, err
+	}
+
+	err = hstore.Start(ctx)
+	if err != nil {
+		s.Close() //nolint: errcheck
+		return nil, nil, err
+	}
+	// load the head, if any, so stored headers are not awaited
+	_, err = hstore.Head(ctx)
+	if err != nil && err != header.ErrNoHead {
+		hstore.Stop(ctx) //nolint: errcheck
+		s.Close()        //nolint: errcheck
+		return nil, nil, err
+	}
+
+	return hstore, func() error {
+		err := hstore.Stop(ctx)
+		if err != nil {
+			s.Close() //nolint: errcheck
+			return err
+		}
+		return s.Close()
+	}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/celestiaorg/celestia-node/nodebuilder/core"
+	"github.com/celestiaorg/celestia-node/nodebuilder/daser"
 	"github.com/celestiaorg/celestia-node/nodebuilder/header"
 	"github.com/celestiaorg/celestia-node/nodebuilder/p2p"
 	"github.com/celestiaorg/celestia-node/nodebuilder/rpc"
@@ -23,6 +24,7 @@ func init() {
 			cmdnode.NodeFlags(),
 			p2p.Flags(),
 			header.Flags(),
+			daser.Flags(),
 			cmdnode.MiscFlags(),
 			// NOTE: for now, state-related queries can only be accessed
 			// over an RPC connection with a celestia-core node.
@@ -34,6 +36,7 @@ func init() {
 			cmdnode.NodeFlags(),
 			p2p.Flags(),
 			header.Flags(),
+			daser.Flags(),
 			cmdnode.MiscFlags(),
 			// NOTE: for now, state-related queries can only be accessed
 			// over an RPC connection with a celestia-core node.
@@ -78,6 +81,11 @@ var fullCmd = &cobra.Command{
 			return err
 		}
 
+		err = daser.ParseFlags(cmd, &cfg.DASer)
+		if err != nil {
+			return err
+		}
+
 		ctx, err = cmdnode.ParseMiscFlags(ctx, cmd)
 		if err != nil {
 			return err
@@ -57,14 +57,30 @@ func WithNodeOptions(ctx context.Context, opts ...fx.Option) context.Context {
 	return context.WithValue(ctx, optionsKey{}, append(options, opts...))
 }
 
+// MigrationOptions returns datastore migration options parsed from Environment(Flags, ENV vars, etc)
+func MigrationOptions(ctx context.Context) []nodebuilder.MigrationOption {
+	options, ok := ctx.Value(migrationOptionsKey{}).([]nodebuilder.MigrationOption)
+	if !ok {
+		return []nodebuilder.MigrationOption{}
+	}
+	return options
+}
+
+// WithMigrationOptions adds new datastore migration options to Env.
+func WithMigrationOptions(ctx context.Context, opts ...nodebuilder.MigrationOption) context.Context {
+	options := MigrationOptions(ctx)
+	return context.WithValue(ctx, migrationOptionsKey{}, append(options, opts...))
+}
+
 // WithNodeConfig sets the node config in the Env.
 func WithNodeConfig(ctx context.Context, config *nodebuilder.Config) context.Context {
 	return context.WithValue(ctx, configKey{}, *config)
 }
 
 type (
-	optionsKey   struct{}
-	configKey    struct{}
-	storePathKey struct{}
-	nodeTypeKey  struct{}
+	optionsKey          struct{}
+	migrationOptionsKey struct{}
+	configKey           struct{}
+	storePathKey        struct{}
+	nodeTypeKey         struct{}
 )
@@ -18,6 +18,9 @@ var (
 	nodeStoreFlag   = "node.store"
 	nodeConfigFlag  = "node.config"
 	nodeNetworkFlag = "node.network"
+
+	migrationDryRunFlag = "node.migration.dry-run"
+	migrationBackupFlag = "node.migration.backup"
 )
 
 // NodeFlags gives a set of hardcoded Node package flags.
@@ -39,6 +42,16 @@ func NodeFlags() *flag.FlagSet {
 		"",
 		"The name of the network to connect to, e.g. "+params.ListProvidedNetworks(),
 	)
+	flags.Bool(
+		migrationDryRunFlag,
+		false,
+		"Reports pending migrations of the node datastore without running them or starting the node",
+	)
+	flags.Bool(
+		migrationBackupFlag,
+		false,
+		"Backs up the node datastore before running pending migrations",
+	)
 
 	return flags
 }
@@ -62,6 +75,22 @@ func ParseNodeFlags(ctx context.Context, cmd *cobra.Command) (context.Context, e
 	}
 	ctx = WithStorePath(ctx, store)
 
+	ok, err := cmd.Flags().GetBool(migrationDryRunFlag)
+	if err != nil {
+		return ctx, err
+	}
+	if ok {
+		ctx = WithMigrationOptions(ctx, nodebuilder.WithMigrationDryRun())
+	}
+
+	ok, err = cmd.Flags().GetBool(migrationBackupFlag)
+	if err != nil {
+		return ctx, err
+	}
+	if ok {
+		ctx = WithMigrationOptions(ctx, nodebuilder.WithMigrationBackup())
+	}
+
 	nodeConfig := cmd.Flag(nodeConfigFlag).Value.String()
 	if nodeConfig != "" {
 		// try to load config from given path
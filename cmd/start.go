@@ -22,10 +22,14 @@ Options passed on start override configuration options only on start and are not
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 
-			store, err := nodebuilder.OpenStore(StorePath(ctx))
+			store, err := nodebuilder.OpenStore(StorePath(ctx), MigrationOptions(ctx)...)
 			if err != nil {
 				return err
 			}
+			// the pending migrations are only reported on dry run
+			if dryRun, _ := cmd.Flags().GetBool(migrationDryRunFlag); dryRun {
+				return store.Close()
+			}
 			// override config with all modifiers passed on start
 			cfg := NodeConfig(ctx)
 
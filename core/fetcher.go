@@ -3,13 +3,20 @@ package core
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 	logging "github.com/ipfs/go-log/v2"
 	tmbytes "github.com/tendermint/tendermint/libs/bytes"
 	"github.com/tendermint/tendermint/types"
 )
 
-const newBlockSubscriber = "NewBlock/Events"
+const (
+	newBlockSubscriber = "NewBlock/Events"
+	// stakingParamsPath is the ABCI query path of the staking module parameters.
+	stakingParamsPath = "/cosmos.staking.v1beta1.Query/Params"
+)
 
 var (
 	log                = logging.Logger("core/fetcher")
@@ -192,3 +199,56 @@ func (f *BlockFetcher) IsSyncing(ctx context.Context) (bool, error) {
 	}
 	return resp.SyncInfo.CatchingUp, nil
 }
+
+// UnbondingTime queries Core for the unbonding time of the staking module.
+func (f *BlockFetcher) UnbondingTime(ctx context.Context) (time.Duration, error) {
+	req, err := (&stakingtypes.QueryParamsRequest{}).Marshal()
+	if err != nil {
+		return 0, err
+	}
+	res, err := f.client.ABCIQuery(ctx, stakingParamsPath, req)
+	if err != nil {
+		return 0, err
+	}
+	if !res.Response.IsOK() {
+		return 0, fmt.Errorf("core/fetcher: querying staking params: %s", res.Response.Log)
+	}
+
+	var params stakingtypes.QueryParamsResponse
+	err = params.Unmarshal(res.Response.Value)
+	if err != nil {
+		return 0, fmt.Errorf("core/fetcher: unmarshalling staking params: %w", err)
+	}
+	if params.Params.UnbondingTime <= 0 {
+		return 0, fmt.Errorf("core/fetcher: staking params not found")
+	}
+	return params.Params.UnbondingTime, nil
+}
+
+// BlockTime queries Core for the latest blocks and returns the median time between them.
+// The genesis block is excluded, as its timestamp is arbitrary and may be far in the past.
+func (f *BlockFetcher) BlockTime(ctx context.Context) (time.Duration, error) {
+	// zero heights request the latest blocks, up to the limit of the endpoint
+	res, err := f.client.BlockchainInfo(ctx, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+	// metas are in descending order
+	metas := res.BlockMetas
+	deltas := make([]time.Duration, 0, len(metas))
+	for i := 0; i+1 < len(metas); i++ {
+		next, prev := metas[i].Header, metas[i+1].Header
+		if prev.Height <= 1 || next.Height != prev.Height+1 {
+			continue
+		}
+		deltas = append(deltas, next.Time.Sub(prev.Time))
+	}
+	if len(deltas) == 0 {
+		return 0, fmt.Errorf("core/fetcher: not enough blocks to estimate block time")
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i] < deltas[j]
+	})
+	return deltas[len(deltas)/2], nil
+}
@@ -82,3 +82,32 @@ func TestBlockFetcherHeaderValues(t *testing.T) {
 	assert.Equal(t, nextBlock.ValidatorsHash, hexBytes)
 	require.NoError(t, fetcher.UnsubscribeNewBlockEvent(ctx))
 }
+
+func TestBlockFetcherNetworkParams(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	t.Cleanup(cancel)
+
+	_, client := StartTestClient(ctx, t)
+	fetcher := NewBlockFetcher(client)
+
+	// wait for some blocks to estimate the block time from
+	newBlockChan, err := fetcher.SubscribeNewBlockEvent(ctx)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		select {
+		case <-newBlockChan:
+		case <-ctx.Done():
+			require.NoError(t, ctx.Err())
+		}
+	}
+	require.NoError(t, fetcher.UnsubscribeNewBlockEvent(ctx))
+
+	blockTime, err := fetcher.BlockTime(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, blockTime, time.Duration(0))
+	assert.Less(t, blockTime, time.Second*3)
+
+	// the test app has no staking module
+	_, err = fetcher.UnbondingTime(ctx)
+	require.Error(t, err)
+}
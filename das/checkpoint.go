@@ -9,6 +9,10 @@ type checkpoint struct {
 	NetworkHead uint64 `json:"network_head"`
 	// Failed will be prioritized on restart
 	Failed map[uint64]int `json:"failed,omitempty"`
+	// Unavailable are not retried on restart
+	Unavailable []uint64 `json:"unavailable,omitempty"`
+	// Pruned are kept to report the heights skipped without sampling
+	Pruned []HeightRange `json:"pruned,omitempty"`
 	// Workers will resume on restart from previous state
 	Workers []workerCheckpoint `json:"workers,omitempty"`
 }
@@ -31,6 +35,8 @@ func newCheckpoint(stats SamplingStats) checkpoint {
 		SampleFrom:  stats.CatchupHead + 1,
 		NetworkHead: stats.NetworkHead,
 		Failed:      stats.Failed,
+		Unavailable: stats.Unavailable,
+		Pruned:      stats.Pruned,
 		Workers:     workers,
 	}
 }
@@ -46,5 +52,9 @@ func (c checkpoint) String() string {
 		str += fmt.Sprintf("\nFailed: %v", c.Failed)
 	}
 
+	if len(c.Unavailable) > 0 {
+		str += fmt.Sprintf("\nUnavailable: %v", c.Unavailable)
+	}
+
 	return str
 }
@@ -3,6 +3,7 @@ package das
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/celestiaorg/celestia-node/header"
 )
@@ -15,12 +16,19 @@ type samplingCoordinator struct {
 
 	state coordinatorState
 
+	window samplingWindow
+	// windowCh delivers the lower bound of the sampling window computed in the background
+	windowCh       chan uint64
+	windowUpdating bool
+
 	// resultCh fans-in sampling results from worker to coordinator
 	resultCh chan result
 	// updHeadCh signals to update network head header height
 	updHeadCh chan uint64
 	// waitCh signals to block coordinator for external access to state
 	waitCh chan *sync.WaitGroup
+	// alerts notifies the subscribers about the heights given up on
+	alerts *alerts
 
 	workersWg sync.WaitGroup
 	metrics   *metrics
@@ -31,34 +39,35 @@ type samplingCoordinator struct {
 type result struct {
 	job
 	failed []uint64
+	pruned []uint64
 	err    error
 }
 
 func newSamplingCoordinator(
-	concurrency int,
-	samplingRange uint64,
+	params Parameters,
 	getter header.Getter,
 	sample sampleFn) *samplingCoordinator {
 	return &samplingCoordinator{
-		concurrencyLimit: concurrency,
+		concurrencyLimit: params.ConcurrencyLimit,
 		getter:           getter,
 		sampleFn:         sample,
-		state:            newCoordinatorState(samplingRange),
+		state:            newCoordinatorState(params),
+		window:           samplingWindow{period: params.SamplingWindow, heights: params.SamplingWindowHeights},
+		windowCh:         make(chan uint64),
 		resultCh:         make(chan result),
 		updHeadCh:        make(chan uint64),
 		waitCh:           make(chan *sync.WaitGroup),
+		alerts:           newAlerts(),
 		done:             newDone("sampling coordinator"),
 	}
 }
 
 func (sc *samplingCoordinator) run(ctx context.Context, cp checkpoint) {
 	sc.state.resumeFromCheckpoint(cp)
-	// resume workers
-	for _, wk := range cp.Workers {
-		sc.runWorker(ctx, sc.state.newJob(wk.From, wk.To))
-	}
+	sc.updateWindow(ctx)
 
 	for {
+		sc.state.queueRetries(time.Now())
 		for !sc.concurrencyLimitReached() {
 			next, found := sc.state.nextJob()
 			if !found {
@@ -67,13 +76,31 @@ func (sc *samplingCoordinator) run(ctx context.Context, cp checkpoint) {
 			sc.runWorker(ctx, next)
 		}
 
+		// wake up for the earliest retry, the due ones are queued on the next iteration
+		var retryTimer *time.Timer
+		var retryCh <-chan time.Time
+		if at, ok := sc.state.nextRetry(); ok {
+			retryTimer = time.NewTimer(time.Until(at))
+			retryCh = retryTimer.C
+		}
+
 		select {
 		case head := <-sc.updHeadCh:
 			if sc.state.updateHead(head) {
 				sc.metrics.observeNewHead(ctx)
+				sc.updateWindow(ctx)
+			}
+		case from := <-sc.windowCh:
+			sc.windowUpdating = false
+			// zero is sent if the window can't be computed, it is retried on the next head then
+			if from != 0 {
+				sc.state.setWindow(from)
 			}
 		case res := <-sc.resultCh:
-			sc.state.handleResult(res)
+			for _, alert := range sc.state.handleResult(res, time.Now()) {
+				sc.alerts.publish(alert)
+			}
+		case <-retryCh:
 		case wg := <-sc.waitCh:
 			wg.Wait()
 		case <-ctx.Done():
@@ -81,11 +108,16 @@ func (sc *samplingCoordinator) run(ctx context.Context, cp checkpoint) {
 			sc.indicateDone()
 			return
 		}
+
+		if retryTimer != nil {
+			retryTimer.Stop()
+		}
 	}
 }
 
 // runWorker runs job in separate worker go-routine
 func (sc *samplingCoordinator) runWorker(ctx context.Context, j job) {
+	j.retries = sc.state.retries(j.From, j.To)
 	w := newWorker(j)
 	sc.state.putInProgress(j.id, w.getState)
 
@@ -97,6 +129,38 @@ func (sc *samplingCoordinator) runWorker(ctx context.Context, j job) {
 	}()
 }
 
+// updateWindow computes the sampling window for the current network head in the background,
+// as it may have to wait for the headers within it to be synced.
+func (sc *samplingCoordinator) updateWindow(ctx context.Context) {
+	// the window is being computed already or there is no known head to compute it for
+	if !sc.window.enabled() || sc.windowUpdating || sc.state.networkHead == sc.state.genesisHeight {
+		return
+	}
+
+	head, genesis := sc.state.networkHead, sc.state.genesisHeight
+	if sc.window.period <= 0 {
+		// the window by the amount of heights is known right away
+		from, _ := sc.window.from(ctx, sc.getter, head, genesis)
+		sc.state.setWindow(from)
+		return
+	}
+
+	sc.windowUpdating = true
+	sc.workersWg.Add(1)
+	go func() {
+		defer sc.workersWg.Done()
+		from, err := sc.window.from(ctx, sc.getter, head, genesis)
+		if err != nil && ctx.Err() == nil {
+			log.Errorw("computing sampling window", "head", head, "err", err)
+		}
+
+		select {
+		case sc.windowCh <- from:
+		case <-ctx.Done():
+		}
+	}()
+}
+
 // listen notifies the coordinator about a new network head received via subscription.
 func (sc *samplingCoordinator) listen(ctx context.Context, height uint64) {
 	select {
@@ -120,12 +184,19 @@ func (sc *samplingCoordinator) stats(ctx context.Context) (SamplingStats, error)
 	return sc.state.unsafeStats(), nil
 }
 
+// getCheckpoint pauses the coordinator to get the checkpoint in a concurrently safe manner
 func (sc *samplingCoordinator) getCheckpoint(ctx context.Context) (checkpoint, error) {
-	stats, err := sc.stats(ctx)
-	if err != nil {
-		return checkpoint{}, err
+	var wg sync.WaitGroup
+	wg.Add(1)
+	defer wg.Done()
+
+	select {
+	case sc.waitCh <- &wg:
+	case <-ctx.Done():
+		return checkpoint{}, ctx.Err()
 	}
-	return newCheckpoint(stats), nil
+
+	return sc.state.unsafeCheckpoint(), nil
 }
 
 // concurrencyLimitReached indicates whether concurrencyLimit has been reached
@@ -11,13 +11,14 @@ import (
 	"github.com/celestiaorg/celestia-node/header"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCoordinator(t *testing.T) {
 	concurrency := 10
 	samplingRange := uint64(10)
 	networkHead := uint64(500)
-	sampleFrom := uint64(genesisHeight)
+	sampleFrom := uint64(1)
 	timeoutDelay := 125 * time.Second
 
 	t.Run("test run", func(t *testing.T) {
@@ -25,7 +26,8 @@ func TestCoordinator(t *testing.T) {
 
 		sampler := newMockSampler(sampleFrom, networkHead)
 
-		coordinator := newSamplingCoordinator(concurrency, samplingRange, getterStub{}, onceMiddleWare(sampler.sample))
+		coordinator := newSamplingCoordinator(testParams(concurrency, samplingRange), getterStub{},
+			onceMiddleWare(sampler.sample))
 		go coordinator.run(ctx, sampler.checkpoint)
 
 		// check if all jobs were sampled successfully
@@ -47,7 +49,7 @@ func TestCoordinator(t *testing.T) {
 
 		sampler := newMockSampler(sampleFrom, networkHead)
 
-		coordinator := newSamplingCoordinator(concurrency, samplingRange, getterStub{}, sampler.sample)
+		coordinator := newSamplingCoordinator(testParams(concurrency, samplingRange), getterStub{}, sampler.sample)
 		go coordinator.run(ctx, sampler.checkpoint)
 
 		time.Sleep(50 * time.Millisecond)
@@ -91,7 +93,7 @@ func TestCoordinator(t *testing.T) {
 		order.addInterval(samplingRange+1, toBeDiscovered)
 
 		// start coordinator
-		coordinator := newSamplingCoordinator(concurrency, samplingRange, getterStub{},
+		coordinator := newSamplingCoordinator(testParams(concurrency, samplingRange), getterStub{},
 			lk.middleWare(
 				order.middleWare(
 					sampler.sample)),
@@ -131,7 +133,7 @@ func TestCoordinator(t *testing.T) {
 		sampler := newMockSampler(sampleFrom, networkHead)
 
 		lk := newLock(sampleFrom, networkHead) // lock all workers before start
-		coordinator := newSamplingCoordinator(concurrency, samplingRange, getterStub{},
+		coordinator := newSamplingCoordinator(testParams(concurrency, samplingRange), getterStub{},
 			lk.middleWare(sampler.sample))
 		go coordinator.run(ctx, sampler.checkpoint)
 
@@ -178,7 +180,8 @@ func TestCoordinator(t *testing.T) {
 		bornToFail := []uint64{4, 8, 15, 16, 23, 42}
 		sampler := newMockSampler(sampleFrom, networkHead, bornToFail...)
 
-		coordinator := newSamplingCoordinator(concurrency, samplingRange, getterStub{}, onceMiddleWare(sampler.sample))
+		coordinator := newSamplingCoordinator(testParams(concurrency, samplingRange), getterStub{},
+			onceMiddleWare(sampler.sample))
 		go coordinator.run(ctx, sampler.checkpoint)
 
 		// wait for coordinator to indicateDone catchup
@@ -207,7 +210,8 @@ func TestCoordinator(t *testing.T) {
 		sampler := newMockSampler(sampleFrom, networkHead, failedAgain...)
 		sampler.checkpoint.Failed = failedLastRun
 
-		coordinator := newSamplingCoordinator(concurrency, samplingRange, getterStub{}, onceMiddleWare(sampler.sample))
+		coordinator := newSamplingCoordinator(testParams(concurrency, samplingRange), getterStub{},
+			onceMiddleWare(sampler.sample))
 		go coordinator.run(ctx, sampler.checkpoint)
 
 		// check if all jobs were sampled successfully
@@ -237,8 +241,8 @@ func BenchmarkCoordinator(b *testing.B) {
 
 	b.Run("bench run", func(b *testing.B) {
 		ctx, cancel := context.WithTimeout(context.Background(), timeoutDelay)
-		coordinator := newSamplingCoordinator(concurrency, samplingRange, newBenchGetter(),
-			func(ctx context.Context, h *header.ExtendedHeader) error { return nil })
+		coordinator := newSamplingCoordinator(testParams(concurrency, samplingRange), newBenchGetter(),
+			func(ctx context.Context, h *header.ExtendedHeader, _ int) error { return nil })
 		go coordinator.run(ctx, checkpoint{
 			SampleFrom:  1,
 			NetworkHead: uint64(b.N),
@@ -282,7 +286,7 @@ func newMockSampler(sampledBefore, sampleTo uint64, bornToFail ...uint64) mockSa
 	}
 }
 
-func (m *mockSampler) sample(ctx context.Context, h *header.ExtendedHeader) error {
+func (m *mockSampler) sample(ctx context.Context, h *header.ExtendedHeader, _ int) error {
 	if err := ctx.Err(); err != nil {
 		return err
 	}
@@ -418,7 +422,7 @@ func TestStack(t *testing.T) {
 }
 
 func (o *checkOrder) middleWare(out sampleFn) sampleFn {
-	return func(ctx context.Context, h *header.ExtendedHeader) error {
+	return func(ctx context.Context, h *header.ExtendedHeader, retries int) error {
 		o.lock.Lock()
 
 		if len(o.queue) > 0 {
@@ -431,7 +435,7 @@ func (o *checkOrder) middleWare(out sampleFn) sampleFn {
 		}
 
 		o.lock.Unlock()
-		return out(ctx, h)
+		return out(ctx, h, retries)
 	}
 }
 
@@ -491,17 +495,17 @@ func (l *lock) releaseAll(except ...uint64) {
 }
 
 func (l *lock) middleWare(out sampleFn) sampleFn {
-	return func(ctx context.Context, h *header.ExtendedHeader) error {
+	return func(ctx context.Context, h *header.ExtendedHeader, retries int) error {
 		l.m.Lock()
 		ch, blocked := l.blockList[uint64(h.Height)]
 		l.m.Unlock()
 		if !blocked {
-			return out(ctx, h)
+			return out(ctx, h, retries)
 		}
 
 		select {
 		case <-ch:
-			return out(ctx, h)
+			return out(ctx, h, retries)
 		case <-ctx.Done():
 			return ctx.Err()
 		}
@@ -511,7 +515,7 @@ func (l *lock) middleWare(out sampleFn) sampleFn {
 func onceMiddleWare(out sampleFn) sampleFn {
 	db := make(map[int64]int)
 	m := sync.Mutex{}
-	return func(ctx context.Context, h *header.ExtendedHeader) error {
+	return func(ctx context.Context, h *header.ExtendedHeader, retries int) error {
 		m.Lock()
 		db[h.Height]++
 		if db[h.Height] > 1 {
@@ -519,6 +523,244 @@ func onceMiddleWare(out sampleFn) sampleFn {
 			return fmt.Errorf("header sampled more than once: %v", h.Height)
 		}
 		m.Unlock()
-		return out(ctx, h)
+		return out(ctx, h, retries)
 	}
 }
+
+// testParams returns the default Parameters with the given concurrency limit and sampling range.
+func testParams(concurrency int, samplingRange uint64) Parameters {
+	params := DefaultParameters()
+	params.ConcurrencyLimit = concurrency
+	params.SamplingRange = samplingRange
+	return params
+}
+
+func TestCoordinator_SamplingWindow(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	t.Cleanup(cancel)
+
+	networkHead := uint64(500)
+	params := testParams(10, 10)
+	params.SamplingWindowHeights = 100
+
+	sampler := newMockSampler(401, networkHead)
+	// the catch-up and the failed heights below the window are skipped
+	cp := checkpoint{
+		SampleFrom:  1,
+		NetworkHead: networkHead,
+		Failed:      map[uint64]int{50: 1, 450: 1},
+	}
+	coordinator := newSamplingCoordinator(params, getterStub{}, sampler.sample)
+	go coordinator.run(ctx, cp)
+
+	assert.NoError(t, sampler.finished(ctx), "not all headers were sampled")
+	assert.NoError(t, coordinator.state.waitCatchUp(ctx))
+	for h := uint64(1); h < 401; h++ {
+		assert.False(t, sampler.heightIsDone(h), "sampled height %d out of the window", h)
+	}
+
+	// the window moves along with the network head
+	sampler.discover(ctx, networkHead+50, coordinator.listen)
+	assert.NoError(t, sampler.finished(ctx))
+	stats, err := coordinator.stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(451), stats.WindowFrom)
+	assert.Equal(t, networkHead+50, stats.WindowTo)
+	assert.Empty(t, stats.Failed)
+
+	cancel()
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer stopCancel()
+	assert.NoError(t, coordinator.wait(stopCtx))
+}
+
+func TestCoordinator_CrashRestart(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	t.Cleanup(cancel)
+
+	networkHead := uint64(2000)
+	concurrency := 8
+	var (
+		lk      sync.Mutex
+		sampled = make(map[uint64]bool)
+	)
+	// sampler samples the heights below the limit and blocks on the rest until the crash
+	sampler := func(limit uint64, blocked chan<- struct{}) sampleFn {
+		return func(ctx context.Context, h *header.ExtendedHeader, _ int) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if uint64(h.Height) >= limit {
+				blocked <- struct{}{}
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			lk.Lock()
+			sampled[uint64(h.Height)] = true
+			lk.Unlock()
+			return nil
+		}
+	}
+
+	cp := checkpoint{SampleFrom: 1, NetworkHead: networkHead}
+	for i := 0; i < 5; i++ {
+		// the range size changes across restarts, so the saved worker ranges don't match the new jobs
+		blocked := make(chan struct{}, concurrency)
+		limit := uint64(300 * (i + 1))
+		coordinator := newSamplingCoordinator(testParams(concurrency, uint64(20-i*3)), getterStub{},
+			sampler(limit, blocked))
+		runCtx, runCancel := context.WithCancel(ctx)
+		go coordinator.run(runCtx, cp)
+
+		// once all the workers are blocked, the ones in progress are checkpointed deterministically
+		for j := 0; j < concurrency; j++ {
+			select {
+			case <-blocked:
+			case <-ctx.Done():
+				t.Fatal(ctx.Err())
+			}
+		}
+		var err error
+		cp, err = coordinator.getCheckpoint(ctx)
+		require.NoError(t, err)
+		require.Len(t, cp.Workers, concurrency, "the workers in progress are not checkpointed")
+		runCancel()
+		require.NoError(t, coordinator.wait(ctx))
+	}
+
+	blocked := make(chan struct{})
+	coordinator := newSamplingCoordinator(testParams(concurrency, 10), getterStub{},
+		sampler(networkHead+1, blocked))
+	go coordinator.run(ctx, cp)
+	require.NoError(t, coordinator.state.waitCatchUp(ctx))
+
+	lk.Lock()
+	defer lk.Unlock()
+	for h := uint64(1); h <= networkHead; h++ {
+		assert.True(t, sampled[h], "height %d is skipped", h)
+	}
+}
+
+func TestCoordinator_Retry(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	t.Cleanup(cancel)
+
+	networkHead := uint64(100)
+	params := testParams(4, 10)
+	params.RetryBackoff = time.Millisecond * 10
+	params.RetryMaxBackoff = time.Millisecond * 20
+	params.MaxAttempts = 3
+
+	// 7 never becomes available, while 13 does on the last attempt
+	var (
+		lk       sync.Mutex
+		attempts = make(map[uint64]int)
+	)
+	sample := func(ctx context.Context, h *header.ExtendedHeader, retries int) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		lk.Lock()
+		defer lk.Unlock()
+		height := uint64(h.Height)
+		// the retries are counted by the coordinator
+		assert.Equal(t, attempts[height], retries, "height %d", height)
+		attempts[height]++
+		if height == 7 || (height == 13 && attempts[height] < 3) {
+			return errors.New("unavailable")
+		}
+		return nil
+	}
+
+	coordinator := newSamplingCoordinator(params, getterStub{}, sample)
+	alerts := coordinator.alerts.subscribe(ctx)
+	go coordinator.run(ctx, checkpoint{SampleFrom: 1, NetworkHead: networkHead})
+
+	select {
+	case alert := <-alerts:
+		assert.Equal(t, UnavailableAlert{Height: 7, Attempts: 3}, alert)
+	case <-ctx.Done():
+		t.Fatal("no alert about the unavailable height")
+	}
+
+	require.Eventually(t, func() bool {
+		stats, err := coordinator.stats(ctx)
+		require.NoError(t, err)
+		return len(stats.Failed) == 0 && stats.SampledChainHead == networkHead
+	}, time.Second*5, time.Millisecond*10)
+
+	cp, err := coordinator.getCheckpoint(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{7}, cp.Unavailable)
+	assert.Empty(t, cp.Failed)
+
+	cancel()
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer stopCancel()
+	assert.NoError(t, coordinator.wait(stopCtx))
+
+	// the subscription is closed along with the context
+	_, ok := <-alerts
+	assert.False(t, ok)
+
+	lk.Lock()
+	defer lk.Unlock()
+	assert.Equal(t, 3, attempts[7])
+	assert.Equal(t, 3, attempts[13])
+}
+
+func TestCoordinator_PrunedHeaders(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	t.Cleanup(cancel)
+
+	networkHead := uint64(100)
+	var (
+		lk      sync.Mutex
+		sampled = make(map[uint64]bool)
+	)
+	sample := func(ctx context.Context, h *header.ExtendedHeader, _ int) error {
+		lk.Lock()
+		defer lk.Unlock()
+		sampled[uint64(h.Height)] = true
+		return nil
+	}
+
+	getter := prunedGetter{from: 20, to: 45}
+	coordinator := newSamplingCoordinator(testParams(4, 10), getter, sample)
+	go coordinator.run(ctx, checkpoint{SampleFrom: 1, NetworkHead: networkHead})
+	require.NoError(t, coordinator.state.waitCatchUp(ctx))
+
+	// the pruned headers are neither sampled nor reported as failed, but reported as pruned
+	cp, err := coordinator.getCheckpoint(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, cp.Failed)
+	assert.Equal(t, []HeightRange{{From: 20, To: 45}}, cp.Pruned)
+	lk.Lock()
+	for h := uint64(1); h <= networkHead; h++ {
+		assert.Equal(t, h < 20 || h > 45, sampled[h], "height %d", h)
+	}
+	lk.Unlock()
+
+	cancel()
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer stopCancel()
+	assert.NoError(t, coordinator.wait(stopCtx))
+
+	// the pruned ranges survive restart
+	state := newCoordinatorState(testParams(4, 10))
+	state.resumeFromCheckpoint(cp)
+	assert.Equal(t, cp.Pruned, state.unsafeStats().Pruned)
+}
+
+// prunedGetter returns header.ErrPruned for the headers in the inclusive range of heights.
+type prunedGetter struct {
+	getterStub
+	from, to uint64
+}
+
+func (g prunedGetter) GetByHeight(ctx context.Context, height uint64) (*header.ExtendedHeader, error) {
+	if height >= g.from && height <= g.to {
+		return nil, header.ErrPruned
+	}
+	return g.getterStub.GetByHeight(ctx, height)
+}
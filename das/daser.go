@@ -17,33 +17,17 @@ import (
 
 var log = logging.Logger("das")
 
-// TODO: parameters needs performance testing on real network to define optimal values
-const (
-	//  samplingRange is the maximum amount of headers processed in one job.
-	samplingRange = 100
-
-	// concurrencyLimit defines the maximum amount of sampling workers running in parallel.
-	concurrencyLimit = 16
-
-	// backgroundStoreInterval is the period of time for background checkpointStore to perform a checkpoint backup.
-	backgroundStoreInterval = 10 * time.Minute
-
-	// priorityQueueSize defines the size limit of the priority queue
-	priorityQueueSize = concurrencyLimit * 4
-
-	// genesisHeight is the height sampling will start from
-	genesisHeight = 1
-)
-
 // DASer continuously validates availability of data committed to headers.
 type DASer struct {
 	da     share.Availability
 	bcast  fraud.Broadcaster
 	hsub   header.Subscriber // listens for new headers in the network
 	getter header.Getter     // retrieves past headers
+	params Parameters
 
 	sampler    *samplingCoordinator
 	store      checkpointStore
+	results    *resultStore
 	subscriber subscriber
 
 	cancel         context.CancelFunc
@@ -52,7 +36,9 @@ type DASer struct {
 }
 
 type listenFn func(ctx context.Context, height uint64)
-type sampleFn func(context.Context, *header.ExtendedHeader) error
+
+// sampleFn samples the header, which failed to be sampled the given amount of times before.
+type sampleFn func(ctx context.Context, h *header.ExtendedHeader, retries int) error
 
 // NewDASer creates a new DASer.
 func NewDASer(
@@ -61,19 +47,28 @@ func NewDASer(
 	getter header.Getter,
 	dstore datastore.Datastore,
 	bcast fraud.Broadcaster,
-) *DASer {
+	options ...Option,
+) (*DASer, error) {
 	d := &DASer{
 		da:             da,
 		bcast:          bcast,
 		hsub:           hsub,
 		getter:         getter,
+		params:         DefaultParameters(),
 		store:          newCheckpointStore(dstore),
+		results:        newResultStore(dstore),
 		subscriber:     newSubscriber(),
 		subscriberDone: make(chan struct{}),
 	}
-	d.sampler = newSamplingCoordinator(concurrencyLimit, samplingRange, getter, d.sample)
+	for _, applyOpt := range options {
+		applyOpt(d)
+	}
+	if err := d.params.Validate(); err != nil {
+		return nil, err
+	}
+	d.sampler = newSamplingCoordinator(d.params, getter, d.sample)
 
-	return d
+	return d, nil
 }
 
 // Start initiates subscription for new ExtendedHeaders and spawns a sampling routine.
@@ -90,11 +85,11 @@ func (d *DASer) Start(ctx context.Context) error {
 	// load latest DASed checkpoint
 	cp, err := d.store.load(ctx)
 	if err != nil {
-		log.Warnw("checkpoint not found, initializing with height 1")
+		log.Warnw("checkpoint not found, initializing with genesis height", "height", d.params.GenesisHeight)
 
 		cp = checkpoint{
-			SampleFrom:  genesisHeight,
-			NetworkHead: genesisHeight,
+			SampleFrom:  d.params.GenesisHeight,
+			NetworkHead: d.params.GenesisHeight,
 		}
 
 		// attempt to get head info. No need to handle error, later DASer
@@ -110,7 +105,8 @@ func (d *DASer) Start(ctx context.Context) error {
 
 	go d.sampler.run(runCtx, cp)
 	go d.subscriber.run(runCtx, sub, d.sampler.listen)
-	go d.store.runBackgroundStore(runCtx, backgroundStoreInterval, d.sampler.getCheckpoint)
+	go d.store.runBackgroundStore(runCtx, d.params.BackgroundStoreInterval, d.sampler.getCheckpoint)
+	go d.results.runBackgroundPrune(runCtx, resultsPruningInterval, d.getter)
 
 	return nil
 }
@@ -137,18 +133,27 @@ func (d *DASer) Stop(ctx context.Context) error {
 	}
 
 	// save updated checkpoint after sampler and all workers are shut down
-	if err = d.store.store(ctx, newCheckpoint(d.sampler.state.unsafeStats())); err != nil {
+	if err = d.store.store(ctx, d.sampler.state.unsafeCheckpoint()); err != nil {
 		log.Errorw("storing checkpoint to disk", "Err", err)
 	}
 
 	if err = d.store.wait(ctx); err != nil {
 		return fmt.Errorf("DASer force quit with err: %w", err)
 	}
+	if err = d.results.wait(ctx); err != nil {
+		return fmt.Errorf("DASer force quit with err: %w", err)
+	}
 	return d.subscriber.wait(ctx)
 }
 
-func (d *DASer) sample(ctx context.Context, h *header.ExtendedHeader) error {
+func (d *DASer) sample(ctx context.Context, h *header.ExtendedHeader, retries int) error {
+	cached := d.isCached(ctx, h)
+	start := time.Now()
 	err := d.da.SharesAvailable(ctx, h.DAH)
+	// interrupted sampling is retried on restart, so there is nothing to record
+	if !errors.Is(err, context.Canceled) {
+		d.recordResult(ctx, h, start, cached, retries, err)
+	}
 	if err != nil {
 		if err == context.Canceled {
 			return err
@@ -173,3 +178,60 @@ func (d *DASer) sample(ctx context.Context, h *header.ExtendedHeader) error {
 func (d *DASer) SamplingStats(ctx context.Context) (SamplingStats, error) {
 	return d.sampler.stats(ctx)
 }
+
+// SubscribeUnavailable subscribes to the alerts about the heights which failed the maximum amount
+// of sampling attempts and are considered permanently unavailable. The channel is closed once
+// the context is done. Alerts are dropped for the subscribers not keeping up with them.
+func (d *DASer) SubscribeUnavailable(ctx context.Context) <-chan UnavailableAlert {
+	return d.sampler.alerts.subscribe(ctx)
+}
+
+// SamplingResults returns the records of sampling of the sampled heights in the given range [from:to).
+// The range must not exceed MaxResultsRange.
+func (d *DASer) SamplingResults(ctx context.Context, from, to uint64) ([]SamplingResult, error) {
+	return d.results.getRange(ctx, from, to)
+}
+
+// isCached reports whether availability of the header's data was validated before,
+// so the share.Availability does not sample it again.
+func (d *DASer) isCached(ctx context.Context, h *header.ExtendedHeader) bool {
+	c, ok := d.da.(share.SampleCache)
+	if !ok {
+		return false
+	}
+	cached, err := c.IsCached(ctx, h.DAH)
+	if err != nil {
+		log.Debugw("checking sampling cache", "height", h.Height, "err", err)
+	}
+	return cached
+}
+
+// recordResult persists the result of the sampling of the header started at the given time.
+func (d *DASer) recordResult(
+	ctx context.Context,
+	h *header.ExtendedHeader,
+	start time.Time,
+	cached bool,
+	retries int,
+	err error,
+) {
+	res := SamplingResult{
+		Height:      uint64(h.Height),
+		Timestamp:   start,
+		SquareWidth: len(h.DAH.RowsRoots),
+		Duration:    time.Since(start),
+		Available:   err == nil,
+		Cached:      cached,
+		Retries:     retries,
+	}
+	if counter, ok := d.da.(share.SampleCounter); ok && !cached {
+		res.Samples = counter.SampleAmount(h.DAH)
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	if err = d.results.put(ctx, res); err != nil {
+		log.Errorw("storing sampling result", "height", h.Height, "err", err)
+	}
+}
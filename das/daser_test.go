@@ -27,6 +27,25 @@ import (
 
 var timeout = time.Second * 15
 
+func TestNewDASer_InvalidOptions(t *testing.T) {
+	ds := ds_sync.MutexWrap(datastore.NewMapDatastore())
+	avail := light.TestAvailability(mdutils.Bserv())
+
+	_, err := NewDASer(avail, nil, nil, ds, nil, WithSamplingRange(0))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+	_, err = NewDASer(avail, nil, nil, ds, nil, WithConcurrencyLimit(-1))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+	_, err = NewDASer(avail, nil, nil, ds, nil, WithRetryBackoff(time.Minute, time.Second))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+	_, err = NewDASer(avail, nil, nil, ds, nil, WithRetryJitter(2))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+
+	daser, err := NewDASer(avail, nil, nil, ds, nil, WithConcurrencyLimit(2), WithGenesisHeight(10))
+	require.NoError(t, err)
+	assert.Equal(t, 2, daser.sampler.concurrencyLimit)
+	assert.Equal(t, uint64(10), daser.sampler.state.next)
+}
+
 // TestDASerLifecycle tests to ensure every mock block is DASed and
 // the DASer checkpoint is updated to network head.
 func TestDASerLifecycle(t *testing.T) {
@@ -39,9 +58,10 @@ func TestDASerLifecycle(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	t.Cleanup(cancel)
 
-	daser := NewDASer(avail, sub, mockGet, ds, mockService)
+	daser, err := NewDASer(avail, sub, mockGet, ds, mockService)
+	require.NoError(t, err)
 
-	err := daser.Start(ctx)
+	err = daser.Start(ctx)
 	require.NoError(t, err)
 	defer func() {
 		err = daser.Stop(ctx)
@@ -61,6 +81,17 @@ func TestDASerLifecycle(t *testing.T) {
 	}
 	// give catch-up routine a second to finish up sampling last header
 	assert.NoError(t, daser.sampler.state.waitCatchUp(ctx))
+
+	// every sampled height is recorded
+	results, err := daser.SamplingResults(ctx, 1, 31)
+	require.NoError(t, err)
+	require.Len(t, results, 30)
+	for i, res := range results {
+		assert.EqualValues(t, i+1, res.Height)
+		assert.True(t, res.Available)
+		assert.NotZero(t, res.Samples)
+		assert.NotZero(t, res.SquareWidth)
+	}
 }
 
 func TestDASer_Restart(t *testing.T) {
@@ -73,9 +104,10 @@ func TestDASer_Restart(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	t.Cleanup(cancel)
 
-	daser := NewDASer(avail, sub, mockGet, ds, mockService)
+	daser, err := NewDASer(avail, sub, mockGet, ds, mockService)
+	require.NoError(t, err)
 
-	err := daser.Start(ctx)
+	err = daser.Start(ctx)
 	require.NoError(t, err)
 
 	// wait for dasing catch-up routine to indicateDone
@@ -100,7 +132,8 @@ func TestDASer_Restart(t *testing.T) {
 	restartCtx, restartCancel := context.WithTimeout(context.Background(), timeout)
 	t.Cleanup(restartCancel)
 
-	daser = NewDASer(avail, sub, mockGet, ds, mockService)
+	daser, err = NewDASer(avail, sub, mockGet, ds, mockService)
+	require.NoError(t, err)
 	err = daser.Start(restartCtx)
 	require.NoError(t, err)
 
@@ -146,7 +179,9 @@ func TestDASer_stopsAfter_BEFP(t *testing.T) {
 	newCtx := context.Background()
 
 	// create and start DASer
-	daser := NewDASer(avail, sub, mockGet, ds, f)
+	daser, err := NewDASer(avail, sub, mockGet, ds, f)
+	require.NoError(t, err)
+
 	resultCh := make(chan error)
 	go fraud.OnProof(newCtx, f, fraud.BadEncoding,
 		func(fraud.Proof) {
@@ -0,0 +1,174 @@
+package das
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidOption is returned by NewDASer when it's given an invalid option value.
+var ErrInvalidOption = errors.New("das: invalid option")
+
+// errInvalidOptionValue wraps ErrInvalidOption with the details of the invalid value.
+func errInvalidOptionValue(name string, value interface{}) error {
+	return fmt.Errorf("%w: value %s cannot be %v", ErrInvalidOption, name, value)
+}
+
+// Parameters is the set of parameters tuning the sampling.
+// TODO: parameters needs performance testing on real network to define optimal values
+type Parameters struct {
+	// SamplingRange is the maximum amount of headers processed in one job.
+	SamplingRange uint64
+	// ConcurrencyLimit defines the maximum amount of sampling workers running in parallel.
+	ConcurrencyLimit int
+	// BackgroundStoreInterval is the period of time for background checkpointStore to perform a checkpoint backup.
+	BackgroundStoreInterval time.Duration
+	// PriorityQueueSize defines the size limit of the priority queue.
+	PriorityQueueSize int
+	// GenesisHeight is the height sampling will start from.
+	GenesisHeight uint64
+	// SamplingWindow limits sampling to the headers with the timestamp within the period before now.
+	// Headers aging out of the window are neither sampled nor retried. Zero disables the limit.
+	SamplingWindow time.Duration
+	// SamplingWindowHeights limits sampling to the amount of the most recent heights.
+	// If both window limits are set, headers are sampled while they are within either of them.
+	// Zero disables the limit.
+	SamplingWindowHeights uint64
+	// RetryBackoff is the delay before the first retry of a failed header, doubled with every next attempt.
+	RetryBackoff time.Duration
+	// RetryMaxBackoff is the upper limit of the delay between the retries.
+	RetryMaxBackoff time.Duration
+	// RetryJitter is the fraction of the delay the retries are randomly spread by, within [0:1].
+	RetryJitter float64
+	// MaxAttempts is the amount of failed sampling attempts after which a header is considered
+	// permanently unavailable and is not retried anymore.
+	MaxAttempts int
+}
+
+// DefaultParameters returns the default Parameters of the DASer.
+func DefaultParameters() Parameters {
+	concurrencyLimit := 16
+	return Parameters{
+		SamplingRange:           100,
+		ConcurrencyLimit:        concurrencyLimit,
+		BackgroundStoreInterval: 10 * time.Minute,
+		PriorityQueueSize:       concurrencyLimit * 4,
+		GenesisHeight:           1,
+		SamplingWindow:          0,
+		SamplingWindowHeights:   0,
+		RetryBackoff:            time.Minute,
+		RetryMaxBackoff:         time.Hour,
+		RetryJitter:             0.2,
+		MaxAttempts:             10,
+	}
+}
+
+// Validate validates the values of the Parameters.
+func (p *Parameters) Validate() error {
+	if p.SamplingRange == 0 {
+		return errInvalidOptionValue("SamplingRange", "zero")
+	}
+	if p.ConcurrencyLimit <= 0 {
+		return errInvalidOptionValue("ConcurrencyLimit", "negative or zero")
+	}
+	if p.BackgroundStoreInterval <= 0 {
+		return errInvalidOptionValue("BackgroundStoreInterval", "negative or zero")
+	}
+	if p.PriorityQueueSize <= 0 {
+		return errInvalidOptionValue("PriorityQueueSize", "negative or zero")
+	}
+	if p.GenesisHeight == 0 {
+		return errInvalidOptionValue("GenesisHeight", "zero")
+	}
+	if p.SamplingWindow < 0 {
+		return errInvalidOptionValue("SamplingWindow", "negative")
+	}
+	if p.RetryBackoff <= 0 {
+		return errInvalidOptionValue("RetryBackoff", "negative or zero")
+	}
+	if p.RetryMaxBackoff < p.RetryBackoff {
+		return errInvalidOptionValue("RetryMaxBackoff", "less than RetryBackoff")
+	}
+	if p.RetryJitter < 0 || p.RetryJitter > 1 {
+		return errInvalidOptionValue("RetryJitter", "out of [0:1]")
+	}
+	if p.MaxAttempts <= 0 {
+		return errInvalidOptionValue("MaxAttempts", "negative or zero")
+	}
+	return nil
+}
+
+// Option configures optional DASer parameters.
+type Option func(*DASer)
+
+// WithSamplingRange sets the maximum amount of headers processed in one job.
+func WithSamplingRange(samplingRange uint64) Option {
+	return func(d *DASer) {
+		d.params.SamplingRange = samplingRange
+	}
+}
+
+// WithConcurrencyLimit sets the maximum amount of sampling workers running in parallel.
+func WithConcurrencyLimit(concurrencyLimit int) Option {
+	return func(d *DASer) {
+		d.params.ConcurrencyLimit = concurrencyLimit
+	}
+}
+
+// WithBackgroundStoreInterval sets the period of time the checkpoint is backed up in the background.
+func WithBackgroundStoreInterval(interval time.Duration) Option {
+	return func(d *DASer) {
+		d.params.BackgroundStoreInterval = interval
+	}
+}
+
+// WithPriorityQueueSize sets the size limit of the priority queue of the recent headers.
+func WithPriorityQueueSize(size int) Option {
+	return func(d *DASer) {
+		d.params.PriorityQueueSize = size
+	}
+}
+
+// WithGenesisHeight sets the height sampling starts from, if there is no checkpoint yet.
+func WithGenesisHeight(height uint64) Option {
+	return func(d *DASer) {
+		d.params.GenesisHeight = height
+	}
+}
+
+// WithSamplingWindow limits sampling to the headers with the timestamp within the period before now.
+func WithSamplingWindow(period time.Duration) Option {
+	return func(d *DASer) {
+		d.params.SamplingWindow = period
+	}
+}
+
+// WithSamplingWindowHeights limits sampling to the given amount of the most recent heights.
+func WithSamplingWindowHeights(heights uint64) Option {
+	return func(d *DASer) {
+		d.params.SamplingWindowHeights = heights
+	}
+}
+
+// WithRetryBackoff sets the delay before the first retry of a failed header and the upper limit of the delay,
+// which is doubled with every next attempt.
+func WithRetryBackoff(backoff, maxBackoff time.Duration) Option {
+	return func(d *DASer) {
+		d.params.RetryBackoff = backoff
+		d.params.RetryMaxBackoff = maxBackoff
+	}
+}
+
+// WithRetryJitter sets the fraction of the delay the retries are randomly spread by.
+func WithRetryJitter(jitter float64) Option {
+	return func(d *DASer) {
+		d.params.RetryJitter = jitter
+	}
+}
+
+// WithMaxAttempts sets the amount of failed sampling attempts after which a header is given up on.
+func WithMaxAttempts(attempts int) Option {
+	return func(d *DASer) {
+		d.params.MaxAttempts = attempts
+	}
+}
@@ -0,0 +1,171 @@
+package das
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+var (
+	resultsPrefix = datastore.NewKey("results")
+	// resultsTailKey keeps the lowest height the results are not pruned below
+	resultsTailKey = datastore.NewKey("tail")
+)
+
+var (
+	// MaxResultsRange is the maximum amount of heights SamplingResults can be requested for at once.
+	MaxResultsRange uint64 = 1000
+	// resultsPruningInterval is the interval the results of the pruned headers are removed at.
+	resultsPruningInterval = time.Minute
+)
+
+// SamplingResult is the record of the latest sampling of the header at the height.
+type SamplingResult struct {
+	Height uint64 `json:"height"`
+	// Timestamp is the time the sampling was started at.
+	Timestamp time.Time `json:"timestamp"`
+	// Samples is the amount of shares requested to validate availability of the data,
+	// or zero if it's not reported by the share.Availability or the data was not sampled again.
+	Samples     int           `json:"samples"`
+	SquareWidth int           `json:"square_width"`
+	Duration    time.Duration `json:"duration"`
+	// Available is the result of the sampling.
+	Available bool   `json:"available"`
+	Error     string `json:"error,omitempty"`
+	// Cached is set if availability of the data was validated before, so it was not sampled again.
+	Cached bool `json:"cached"`
+	// Retries is the amount of times sampling of the height failed before.
+	Retries int `json:"retries"`
+}
+
+// resultStore persists the SamplingResult of every sampled height
+// within the retention window of the header store.
+type resultStore struct {
+	ds datastore.Datastore
+	done
+}
+
+// newResultStore wraps the given datastore.Datastore with the `das/results` prefix.
+func newResultStore(ds datastore.Datastore) *resultStore {
+	return &resultStore{
+		ds:   namespace.Wrap(ds, storePrefix.Child(resultsPrefix)),
+		done: newDone("result store"),
+	}
+}
+
+// put stores the result of the sampling, replacing the previous one of the height.
+func (s *resultStore) put(ctx context.Context, res SamplingResult) error {
+	bs, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	return s.ds.Put(ctx, resultKey(res.Height), bs)
+}
+
+// get loads the result of the sampling of the given height.
+func (s *resultStore) get(ctx context.Context, height uint64) (SamplingResult, error) {
+	bs, err := s.ds.Get(ctx, resultKey(height))
+	if err != nil {
+		return SamplingResult{}, err
+	}
+
+	var res SamplingResult
+	return res, json.Unmarshal(bs, &res)
+}
+
+// getRange loads the results of the sampled heights in the given range [from:to).
+func (s *resultStore) getRange(ctx context.Context, from, to uint64) ([]SamplingResult, error) {
+	if from == 0 || from >= to {
+		return nil, fmt.Errorf("das: invalid range of heights [%d:%d)", from, to)
+	}
+	if to-from > MaxResultsRange {
+		return nil, fmt.Errorf("das: range of heights [%d:%d) exceeds the limit of %d", from, to, MaxResultsRange)
+	}
+
+	results := make([]SamplingResult, 0, to-from)
+	for h := from; h < to; h++ {
+		res, err := s.get(ctx, h)
+		if errors.Is(err, datastore.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// tailGetter is implemented by the header stores pruning the headers out of their retention window.
+type tailGetter interface {
+	Tail(context.Context) (*header.ExtendedHeader, error)
+}
+
+// runBackgroundPrune periodically removes the results of the heights pruned from the header store,
+// so the results are kept within the same retention window. The routine is disabled
+// if the getter does not report its tail.
+func (s *resultStore) runBackgroundPrune(ctx context.Context, interval time.Duration, getter header.Getter) {
+	defer s.indicateDone()
+
+	tg, ok := getter.(tailGetter)
+	if !ok {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		tail, err := tg.Tail(ctx)
+		if err != nil {
+			log.Debugw("getting tail of the header store", "err", err)
+			continue
+		}
+		if err = s.prune(ctx, uint64(tail.Height)); err != nil && ctx.Err() == nil {
+			log.Errorw("pruning sampling results", "tail", tail.Height, "err", err)
+		}
+	}
+}
+
+// prune removes the results of the heights below the given tail.
+func (s *resultStore) prune(ctx context.Context, tail uint64) error {
+	from := uint64(1)
+	bs, err := s.ds.Get(ctx, resultsTailKey)
+	switch {
+	case err == nil:
+		from, err = strconv.ParseUint(string(bs), 10, 64)
+		if err != nil {
+			return err
+		}
+	case errors.Is(err, datastore.ErrNotFound):
+	default:
+		return err
+	}
+
+	if from >= tail {
+		return nil
+	}
+	for h := from; h < tail; h++ {
+		if err = s.ds.Delete(ctx, resultKey(h)); err != nil {
+			return err
+		}
+	}
+	return s.ds.Put(ctx, resultsTailKey, []byte(strconv.FormatUint(tail, 10)))
+}
+
+func resultKey(height uint64) datastore.Key {
+	return datastore.NewKey(strconv.FormatUint(height, 10))
+}
@@ -0,0 +1,64 @@
+package das
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultStore(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	store := newResultStore(sync.MutexWrap(datastore.NewMapDatastore()))
+	for _, h := range []uint64{1, 2, 3, 5} {
+		err := store.put(ctx, SamplingResult{Height: h, Samples: 16, SquareWidth: 4, Available: true})
+		require.NoError(t, err)
+	}
+	// the result of the retry replaces the failed one
+	sampleErr := errors.New("not available")
+	require.NoError(t, store.put(ctx, SamplingResult{Height: 4, Error: sampleErr.Error()}))
+	require.NoError(t, store.put(ctx, SamplingResult{Height: 4, Available: true, Retries: 1}))
+
+	results, err := store.getRange(ctx, 2, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, SamplingResult{Height: 2, Samples: 16, SquareWidth: 4, Available: true}, results[0])
+	assert.Equal(t, SamplingResult{Height: 4, Available: true, Retries: 1}, results[2])
+	assert.EqualValues(t, 5, results[3].Height)
+
+	_, err = store.getRange(ctx, 5, 5)
+	require.Error(t, err)
+	_, err = store.getRange(ctx, 1, MaxResultsRange+2)
+	require.Error(t, err)
+}
+
+func TestResultStore_Prune(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	store := newResultStore(sync.MutexWrap(datastore.NewMapDatastore()))
+	for h := uint64(1); h <= 10; h++ {
+		require.NoError(t, store.put(ctx, SamplingResult{Height: h, Available: true}))
+	}
+
+	require.NoError(t, store.prune(ctx, 4))
+	results, err := store.getRange(ctx, 1, 11)
+	require.NoError(t, err)
+	require.Len(t, results, 7)
+	assert.EqualValues(t, 4, results[0].Height)
+
+	// pruning continues from the previous tail and never goes back
+	require.NoError(t, store.prune(ctx, 8))
+	require.NoError(t, store.prune(ctx, 6))
+	results, err = store.getRange(ctx, 1, 11)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.EqualValues(t, 8, results[0].Height)
+}
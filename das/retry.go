@@ -0,0 +1,170 @@
+package das
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// retryPolicy defines when the failed heights are retried and when they are given up on.
+type retryPolicy struct {
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	jitter      float64
+	maxAttempts int
+
+	// rand is seeded uniquely, unlike the global one, so that the retries are spread out across the nodes.
+	// It's only used by the coordinator's routine, so it needs no synchronization.
+	rand *rand.Rand
+}
+
+func newRetryPolicy(params Parameters) retryPolicy {
+	return retryPolicy{
+		backoff:     params.RetryBackoff,
+		maxBackoff:  params.RetryMaxBackoff,
+		jitter:      params.RetryJitter,
+		maxAttempts: params.MaxAttempts,
+		rand:        rand.New(rand.NewSource(time.Now().UnixNano())), //nolint:gosec
+	}
+}
+
+// delay returns the time to wait for before the next attempt, after the given amount of failed ones.
+// The delay doubles with every attempt up to the maximum, and is randomized by the jitter
+// so that the retries of the heights failed at once are spread out.
+func (p retryPolicy) delay(attempts int) time.Duration {
+	delay := float64(p.backoff) * math.Pow(2, float64(attempts-1))
+	if delay > float64(p.maxBackoff) {
+		delay = float64(p.maxBackoff)
+	}
+	if p.jitter > 0 {
+		delay += delay * p.jitter * (p.rand.Float64()*2 - 1)
+	}
+	return time.Duration(delay)
+}
+
+// exhausted checks whether the given amount of failed attempts reached the maximum.
+func (p retryPolicy) exhausted(attempts int) bool {
+	return attempts >= p.maxAttempts
+}
+
+// scheduleRetry schedules the next attempt of the failed height or, if the attempts are exhausted,
+// moves it to the permanently unavailable ones. Returns whether the height became unavailable.
+func (s *coordinatorState) scheduleRetry(h uint64, now time.Time) bool {
+	attempts := s.failed[h]
+	if s.retry.exhausted(attempts) {
+		delete(s.failed, h)
+		delete(s.retryAt, h)
+		s.unavailable[h] = struct{}{}
+		log.Warnw("giving up on sampling of the height", "height", h, "attempts", attempts)
+		return true
+	}
+	s.retryAt[h] = now.Add(s.retry.delay(attempts))
+	return false
+}
+
+// queueRetries puts the failed heights due to be retried into priority.
+func (s *coordinatorState) queueRetries(now time.Time) {
+	for h, at := range s.retryAt {
+		if at.After(now) {
+			continue
+		}
+		delete(s.retryAt, h)
+		s.priority = append(s.priority, s.newJob(h, h))
+	}
+	s.checkDone()
+}
+
+// retries returns the amount of failed attempts of the failed heights in the given range.
+func (s *coordinatorState) retries(from, to uint64) map[uint64]int {
+	var retries map[uint64]int
+	for h, attempts := range s.failed {
+		if h < from || h > to {
+			continue
+		}
+		if retries == nil {
+			retries = make(map[uint64]int)
+		}
+		retries[h] = attempts
+	}
+	return retries
+}
+
+// nextRetry returns the time the earliest retry is due at, if any.
+func (s *coordinatorState) nextRetry() (time.Time, bool) {
+	var next time.Time
+	for _, at := range s.retryAt {
+		if next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// unavailableHeights returns the permanently unavailable heights in ascending order.
+func (s *coordinatorState) unavailableHeights() []uint64 {
+	if len(s.unavailable) == 0 {
+		return nil
+	}
+	heights := make([]uint64, 0, len(s.unavailable))
+	for h := range s.unavailable {
+		heights = append(heights, h)
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+	return heights
+}
+
+// UnavailableAlert notifies that sampling of the height failed the maximum amount of attempts,
+// so the data of the height is considered permanently unavailable and is not retried anymore.
+type UnavailableAlert struct {
+	Height   uint64 `json:"height"`
+	Attempts int    `json:"attempts"`
+}
+
+// alertsBufferSize is the amount of alerts buffered for a subscriber before they are dropped.
+const alertsBufferSize = 64
+
+// alerts fans out the UnavailableAlerts to the subscribers.
+type alerts struct {
+	lk   sync.Mutex
+	subs map[chan UnavailableAlert]struct{}
+}
+
+func newAlerts() *alerts {
+	return &alerts{subs: make(map[chan UnavailableAlert]struct{})}
+}
+
+// subscribe subscribes to the alerts until the context is done, closing the channel afterwards.
+func (a *alerts) subscribe(ctx context.Context) <-chan UnavailableAlert {
+	ch := make(chan UnavailableAlert, alertsBufferSize)
+	a.lk.Lock()
+	a.subs[ch] = struct{}{}
+	a.lk.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		a.lk.Lock()
+		delete(a.subs, ch)
+		close(ch)
+		a.lk.Unlock()
+	}()
+	return ch
+}
+
+// publish sends the alert to all the subscribers without blocking,
+// dropping it for the ones not keeping up.
+func (a *alerts) publish(alert UnavailableAlert) {
+	a.lk.Lock()
+	defer a.lk.Unlock()
+	for ch := range a.subs {
+		select {
+		case ch <- alert:
+		default:
+			log.Warnw("dropping unavailable height alert for slow subscriber", "height", alert.Height)
+		}
+	}
+}
@@ -2,36 +2,53 @@ package das
 
 import (
 	"context"
+	"sort"
+	"time"
 )
 
 // coordinatorState represents the current state of sampling
 type coordinatorState struct {
-	rangeSize uint64
-
-	priority   []job                      // list of headers heights that will be sampled with higher priority
-	inProgress map[int]func() workerState // keeps track of running workers
-	failed     map[uint64]int             // stores heights of failed headers with amount of attempt as value
+	rangeSize         uint64
+	priorityQueueSize int
+	genesisHeight     uint64
+	retry             retryPolicy
+
+	priority    []job                      // list of headers heights that will be sampled with higher priority
+	inProgress  map[int]func() workerState // keeps track of running workers
+	failed      map[uint64]int             // stores heights of failed headers with amount of attempt as value
+	retryAt     map[uint64]time.Time       // time the failed heights are scheduled to be retried at
+	unavailable map[uint64]struct{}        // heights failed the maximum amount of attempts, which are not retried anymore
+	pruned      []HeightRange              // ranges of heights skipped without sampling, as their headers were pruned
 
 	nextJobID   int
 	next        uint64 // all headers before next were sent to workers
 	networkHead uint64
 
+	windowFrom  uint64 // the lowest height within the sampling window, if it's enabled
+	windowKnown bool   // catch-up is held until the sampling window is known
+
 	catchUpDone   bool          // indicates if all headers are sampled
 	catchUpDoneCh chan struct{} // blocks until all headers are sampled
 }
 
 // newCoordinatorState initiates state for samplingCoordinator
-func newCoordinatorState(samplingRangeSize uint64) coordinatorState {
+func newCoordinatorState(params Parameters) coordinatorState {
 	return coordinatorState{
-		rangeSize:     samplingRangeSize,
-		priority:      make([]job, 0),
-		inProgress:    make(map[int]func() workerState),
-		failed:        make(map[uint64]int),
-		nextJobID:     0,
-		next:          genesisHeight,
-		networkHead:   genesisHeight,
-		catchUpDone:   false,
-		catchUpDoneCh: make(chan struct{}),
+		rangeSize:         params.SamplingRange,
+		priorityQueueSize: params.PriorityQueueSize,
+		genesisHeight:     params.GenesisHeight,
+		retry:             newRetryPolicy(params),
+		priority:          make([]job, 0),
+		inProgress:        make(map[int]func() workerState),
+		failed:            make(map[uint64]int),
+		retryAt:           make(map[uint64]time.Time),
+		unavailable:       make(map[uint64]struct{}),
+		nextJobID:         0,
+		next:              params.GenesisHeight,
+		networkHead:       params.GenesisHeight,
+		windowKnown:       params.SamplingWindow <= 0 && params.SamplingWindowHeights == 0,
+		catchUpDone:       false,
+		catchUpDoneCh:     make(chan struct{}),
 	}
 }
 
@@ -43,9 +60,32 @@ func (s *coordinatorState) resumeFromCheckpoint(c checkpoint) {
 		s.failed[h] = count
 		s.priority = append(s.priority, s.newJob(h, h))
 	}
+	// permanently unavailable heights are not retried anymore
+	for _, h := range c.Unavailable {
+		s.unavailable[h] = struct{}{}
+	}
+	s.pruned = append(s.pruned, c.Pruned...)
+	// put the rest of the ranges the workers were sampling into priority to resume them on restart.
+	// The ranges are split into jobs by the current range size, as it may differ from the one they were made with.
+	// The heights from SampleFrom are going to be sampled by catch-up anyway, so they are left to it.
+	for _, w := range c.Workers {
+		to := w.To
+		if to >= c.SampleFrom {
+			to = c.SampleFrom - 1
+		}
+		for from := w.From; from <= to; from += s.rangeSize {
+			s.priority = append(s.priority, s.newJob(from, to))
+		}
+	}
+	// priority is taken from the end, so sort it to resume from the lowest heights
+	sort.Slice(s.priority, func(i, j int) bool {
+		return s.priority[i].From > s.priority[j].From
+	})
 }
 
-func (s *coordinatorState) handleResult(res result) {
+// handleResult updates the state with the result of the worker, scheduling retries of the failed heights.
+// Returns the alerts of the heights which failed the maximum amount of attempts and became unavailable.
+func (s *coordinatorState) handleResult(res result, now time.Time) []UnavailableAlert {
 	delete(s.inProgress, res.id)
 
 	failedFromWorker := make(map[uint64]bool)
@@ -61,13 +101,32 @@ func (s *coordinatorState) handleResult(res result) {
 
 		if !failedFromWorker[h] {
 			delete(s.failed, h)
+			delete(s.retryAt, h)
+		}
+	}
+	// the unavailable heights may still be sampled successfully by the ranges resumed on restart
+	for h := range s.unavailable {
+		if h >= res.From && h <= res.To && !failedFromWorker[h] {
+			delete(s.unavailable, h)
 		}
 	}
-	// add newly failed heights
+
+	s.addPruned(res.pruned)
+
+	// add newly failed heights, unless they aged out of the sampling window already or are given up on
+	var alerts []UnavailableAlert
 	for h := range failedFromWorker {
+		if _, ok := s.unavailable[h]; ok || h < s.windowFrom {
+			continue
+		}
 		s.failed[h]++
+		attempts := s.failed[h]
+		if s.scheduleRetry(h, now) {
+			alerts = append(alerts, UnavailableAlert{Height: h, Attempts: attempts})
+		}
 	}
 	s.checkDone()
+	return alerts
 }
 
 func (s *coordinatorState) updateHead(last uint64) bool {
@@ -77,7 +136,7 @@ func (s *coordinatorState) updateHead(last uint64) bool {
 		return false
 	}
 
-	if s.networkHead == genesisHeight {
+	if s.networkHead == s.genesisHeight {
 		s.networkHead = last
 		log.Infow("found first header, starting sampling")
 		return true
@@ -85,7 +144,7 @@ func (s *coordinatorState) updateHead(last uint64) bool {
 
 	// add most recent headers into priority queue
 	from := s.networkHead + 1
-	for from <= last && len(s.priority) < priorityQueueSize {
+	for from <= last && len(s.priority) < s.priorityQueueSize {
 		s.priority = append(s.priority, s.newJob(from, last))
 		from += s.rangeSize
 	}
@@ -98,14 +157,19 @@ func (s *coordinatorState) updateHead(last uint64) bool {
 
 // nextJob will return header height to be processed and done flag if there is none
 func (s *coordinatorState) nextJob() (next job, found bool) {
+	// try to take from priority first, it may have retries of the failed headers after all were sent to workers
+	if next, found := s.nextFromPriority(); found {
+		return next, found
+	}
+
 	// all headers were sent to workers.
 	if s.next > s.networkHead {
 		return job{}, false
 	}
 
-	// try to take from priority first
-	if next, found := s.nextFromPriority(); found {
-		return next, found
+	// the catch-up may start below the sampling window
+	if !s.windowKnown {
+		return job{}, false
 	}
 
 	j := s.newJob(s.next, s.networkHead)
@@ -133,6 +197,85 @@ func (s *coordinatorState) nextFromPriority() (job, bool) {
 	return job{}, false
 }
 
+// setWindow moves the lower bound of the sampling window up. The headers below it
+// are neither sampled nor retried anymore, though the ones being sampled already are finished.
+func (s *coordinatorState) setWindow(from uint64) {
+	s.windowKnown = true
+	if from <= s.windowFrom {
+		s.checkDone()
+		return
+	}
+
+	log.Debugw("sampling window moved", "from_height", from, "network_head", s.networkHead)
+	s.windowFrom = from
+	if s.next < from {
+		s.next = from
+	}
+
+	priority := s.priority[:0]
+	for _, j := range s.priority {
+		if j.To < from {
+			continue
+		}
+		if j.From < from {
+			j.From = from
+		}
+		priority = append(priority, j)
+	}
+	s.priority = priority
+
+	for h := range s.failed {
+		if h < from {
+			delete(s.failed, h)
+			delete(s.retryAt, h)
+		}
+	}
+	for h := range s.unavailable {
+		if h < from {
+			delete(s.unavailable, h)
+		}
+	}
+
+	pruned := s.pruned[:0]
+	for _, r := range s.pruned {
+		if r.To < from {
+			continue
+		}
+		if r.From < from {
+			r.From = from
+		}
+		pruned = append(pruned, r)
+	}
+	s.pruned = pruned
+	s.checkDone()
+}
+
+// addPruned adds the heights skipped without sampling to the pruned ranges, merging the adjacent ones.
+func (s *coordinatorState) addPruned(heights []uint64) {
+	if len(heights) == 0 {
+		return
+	}
+	for _, h := range heights {
+		s.pruned = append(s.pruned, HeightRange{From: h, To: h})
+	}
+
+	sort.Slice(s.pruned, func(i, j int) bool {
+		return s.pruned[i].From < s.pruned[j].From
+	})
+	merged := s.pruned[:1]
+	for _, r := range s.pruned[1:] {
+		last := &merged[len(merged)-1]
+		if r.From <= last.To+1 {
+			if r.To > last.To {
+				last.To = r.To
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	s.pruned = merged
+}
+
 func (s *coordinatorState) putInProgress(jobID int, getState func() workerState) {
 	s.inProgress[jobID] = getState
 }
@@ -182,6 +325,13 @@ func (s *coordinatorState) unsafeStats() SamplingStats {
 		}
 	}
 
+	// the pending priority jobs below the catch-up are not sampled yet either
+	for _, j := range s.priority {
+		if j.From < lowestFailedOrInProgress {
+			lowestFailedOrInProgress = j.From
+		}
+	}
+
 	// set lowestFailedOrInProgress to minimum failed - 1
 	for h, count := range s.failed {
 		failed[h] += count
@@ -190,6 +340,11 @@ func (s *coordinatorState) unsafeStats() SamplingStats {
 		}
 	}
 
+	var windowTo uint64
+	if s.windowFrom != 0 {
+		windowTo = s.networkHead
+	}
+
 	return SamplingStats{
 		SampledChainHead: lowestFailedOrInProgress - 1,
 		CatchupHead:      s.next - 1,
@@ -199,7 +354,29 @@ func (s *coordinatorState) unsafeStats() SamplingStats {
 		Concurrency:      len(workers),
 		CatchUpDone:      s.catchUpDone,
 		IsRunning:        len(workers) > 0 || s.catchUpDone,
+		WindowFrom:       s.windowFrom,
+		WindowTo:         windowTo,
+		Unavailable:      s.unavailableHeights(),
+		Pruned:           append([]HeightRange(nil), s.pruned...),
+	}
+}
+
+// unsafeCheckpoint collects the checkpoint without thread-safety. Besides the ranges of the running workers,
+// the ones of the pending priority jobs below the catch-up are saved, as nothing else would resume them.
+func (s *coordinatorState) unsafeCheckpoint() checkpoint {
+	cp := newCheckpoint(s.unsafeStats())
+	for _, j := range s.priority {
+		// failed heights are saved on their own
+		if j.From >= s.next || (j.From == j.To && s.failed[j.From] > 0) {
+			continue
+		}
+		to := j.To
+		if to >= s.next {
+			to = s.next - 1
+		}
+		cp.Workers = append(cp.Workers, workerCheckpoint{From: j.From, To: to})
 	}
+	return cp
 }
 
 func (s *coordinatorState) checkDone() {
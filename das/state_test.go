@@ -4,8 +4,10 @@ import (
 	"errors"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.uber.org/multierr"
 )
@@ -83,3 +85,114 @@ func Test_coordinatorStats(t *testing.T) {
 		})
 	}
 }
+
+func Test_coordinatorResumeFromCheckpoint(t *testing.T) {
+	params := DefaultParameters()
+	params.SamplingRange = 4
+	state := newCoordinatorState(params)
+	state.resumeFromCheckpoint(checkpoint{
+		SampleFrom:  30,
+		NetworkHead: 100,
+		Failed:      map[uint64]int{3: 1},
+		Workers: []workerCheckpoint{
+			// made with a bigger range size
+			{From: 5, To: 14},
+			{From: 25, To: 34},
+			// covered by catch-up
+			{From: 40, To: 49},
+		},
+	})
+
+	// the pending jobs are checkpointed until they are taken by workers
+	cp := state.unsafeCheckpoint()
+	assert.Equal(t, map[uint64]int{3: 1}, cp.Failed)
+	assert.Equal(t, []workerCheckpoint{
+		{From: 29, To: 29},
+		{From: 25, To: 28},
+		{From: 13, To: 14},
+		{From: 9, To: 12},
+		{From: 5, To: 8},
+	}, cp.Workers)
+	// nothing is sampled above the pending jobs
+	assert.Equal(t, uint64(2), state.unsafeStats().SampledChainHead)
+
+	var jobs []job
+	for {
+		j, found := state.nextFromPriority()
+		if !found {
+			break
+		}
+		jobs = append(jobs, job{From: j.From, To: j.To})
+	}
+	assert.Equal(t, []job{
+		{From: 3, To: 3},
+		{From: 5, To: 8},
+		{From: 9, To: 12},
+		{From: 13, To: 14},
+		{From: 25, To: 28},
+		{From: 29, To: 29},
+	}, jobs)
+}
+
+func Test_coordinatorRetry(t *testing.T) {
+	params := DefaultParameters()
+	params.RetryBackoff = time.Second
+	params.RetryMaxBackoff = time.Second * 3
+	params.RetryJitter = 0
+	params.MaxAttempts = 3
+	state := newCoordinatorState(params)
+	state.resumeFromCheckpoint(checkpoint{SampleFrom: 11, NetworkHead: 20})
+
+	now := time.Now()
+	fail := func(from, to uint64, failed ...uint64) []UnavailableAlert {
+		return state.handleResult(result{job: job{From: from, To: to}, failed: failed}, now)
+	}
+
+	// the retries are delayed with the backoff doubled on every attempt
+	for attempt, delay := range []time.Duration{time.Second, time.Second * 2} {
+		assert.Empty(t, fail(1, 10, 5))
+		assert.Equal(t, attempt+1, state.failed[5])
+
+		at, ok := state.nextRetry()
+		require.True(t, ok)
+		assert.Equal(t, now.Add(delay), at)
+
+		state.queueRetries(at.Add(-time.Nanosecond))
+		assert.Empty(t, state.priority)
+		now = at
+		state.queueRetries(now)
+		require.Len(t, state.priority, 1)
+		j, _ := state.nextFromPriority()
+		assert.Equal(t, job{id: j.id, From: 5, To: 5}, j)
+	}
+
+	// the height is given up on after the maximum amount of attempts
+	assert.Equal(t, []UnavailableAlert{{Height: 5, Attempts: 3}}, fail(5, 5, 5))
+	assert.Empty(t, state.failed)
+	_, ok := state.nextRetry()
+	assert.False(t, ok)
+	// and is neither counted as failed nor holds the sampled chain head
+	stats := state.unsafeStats()
+	assert.Equal(t, []uint64{5}, stats.Unavailable)
+	assert.Equal(t, uint64(10), stats.SampledChainHead)
+	// nor is counted as failed again, if it's sampled by a range
+	assert.Empty(t, fail(1, 10, 5))
+	assert.Empty(t, state.failed)
+
+	// the unavailable heights are not retried on restart
+	cp := state.unsafeCheckpoint()
+	assert.Equal(t, []uint64{5}, cp.Unavailable)
+	restarted := newCoordinatorState(params)
+	restarted.resumeFromCheckpoint(cp)
+	assert.Empty(t, restarted.priority)
+	assert.Equal(t, []uint64{5}, restarted.unsafeStats().Unavailable)
+
+	// the delay is capped and randomized by the jitter
+	params.RetryJitter = 0.5
+	policy := newRetryPolicy(params)
+	for attempts := 1; attempts < 10; attempts++ {
+		delay := policy.delay(attempts)
+		assert.GreaterOrEqual(t, delay, time.Second/2)
+		assert.LessOrEqual(t, delay, time.Second*3*3/2)
+	}
+}
@@ -5,7 +5,8 @@ package das
 // over current network headers, and the `catchUp` routine which performs sampling
 // over past headers from the last sampled checkpoint.
 type SamplingStats struct {
-	// all headers before SampledChainHead were successfully sampled
+	// all headers before SampledChainHead were successfully sampled,
+	// except the Unavailable ones and the Pruned ones, which were skipped
 	SampledChainHead uint64 `json:"head_of_sampled_chain"`
 	// all headers before CatchupHead were submitted to sampling workers
 	CatchupHead uint64 `json:"head_of_catchup"`
@@ -21,6 +22,22 @@ type SamplingStats struct {
 	CatchUpDone bool `json:"catch_up_done"`
 	// IsRunning tracks whether the DASer service is running
 	IsRunning bool `json:"is_running"`
+	// WindowFrom and WindowTo are the bounds of the sampling window, if one is set.
+	// Headers below WindowFrom are neither sampled nor retried.
+	WindowFrom uint64 `json:"window_from,omitempty"`
+	WindowTo   uint64 `json:"window_to,omitempty"`
+	// Unavailable contains the heights of headers failed the maximum amount of sampling attempts.
+	// They are considered permanently unavailable and are not retried anymore.
+	Unavailable []uint64 `json:"unavailable,omitempty"`
+	// Pruned contains the ranges of heights skipped as their headers were pruned from the store
+	// before they were sampled. Availability of their data was NOT verified.
+	Pruned []HeightRange `json:"pruned,omitempty"`
+}
+
+// HeightRange is an inclusive range of heights.
+type HeightRange struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
 }
 
 type WorkerStats struct {
@@ -0,0 +1,62 @@
+package das
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+// samplingWindow limits sampling to the recent headers, either to those with the timestamp within
+// the period of time before now, or to the amount of the most recent heights.
+// If both are set, a header is sampled while it is within either of them.
+type samplingWindow struct {
+	period  time.Duration
+	heights uint64
+}
+
+// enabled checks whether the sampling is limited by the window.
+func (w samplingWindow) enabled() bool {
+	return w.period > 0 || w.heights > 0
+}
+
+// from finds the lowest height within the window, not lower than the genesis one, for the given head.
+// The height after the head is returned if none of the headers are within the window.
+func (w samplingWindow) from(ctx context.Context, getter header.Getter, head, genesis uint64) (uint64, error) {
+	if head < genesis {
+		return genesis, nil
+	}
+
+	from := head + 1
+	if w.heights > 0 {
+		from = genesis
+		if head-genesis+1 > w.heights {
+			from = head - w.heights + 1
+		}
+	}
+	if w.period <= 0 {
+		return from, nil
+	}
+
+	// binary search for the lowest header with the timestamp within the period,
+	// only the heights below the one found by the amount of heights are of interest
+	start := time.Now().Add(-w.period)
+	lo, hi := genesis, from
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		h, err := getter.GetByHeight(ctx, mid)
+		switch {
+		case errors.Is(err, header.ErrPruned):
+			// pruned headers are as old as the store's retention window, so consider them out of the window
+			lo = mid + 1
+		case err != nil:
+			return 0, err
+		case h.Time.Before(start):
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+	return lo, nil
+}
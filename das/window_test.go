@@ -0,0 +1,68 @@
+package das
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+func TestSamplingWindow_From(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	head := uint64(500)
+	// a header per minute up to now, with the ones below 100 pruned
+	getter := timedGetter{head: head, now: time.Now(), interval: time.Minute, pruned: 100}
+
+	var tests = []struct {
+		name   string
+		window samplingWindow
+		from   uint64
+	}{
+		{"heights", samplingWindow{heights: 50}, 451},
+		{"more heights than the chain", samplingWindow{heights: 1000}, 1},
+		{"period", samplingWindow{period: time.Minute*10 + time.Second*30}, 490},
+		{"period of pruned headers", samplingWindow{period: time.Hour * 24}, 100},
+		{"wider period than heights", samplingWindow{period: time.Minute * 30, heights: 10}, 471},
+		{"wider heights than period", samplingWindow{period: time.Minute * 30, heights: 40}, 461},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, err := tt.window.from(ctx, getter, head, 1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.from, from)
+		})
+	}
+
+	// none of the headers are within the window
+	getter.now = time.Now().Add(-time.Hour)
+	from, err := samplingWindow{period: time.Minute}.from(ctx, getter, head, 1)
+	require.NoError(t, err)
+	assert.Equal(t, head+1, from)
+}
+
+// timedGetter serves headers produced at the given interval, with the head produced at the given time.
+type timedGetter struct {
+	getterStub
+	head     uint64
+	now      time.Time
+	interval time.Duration
+	pruned   uint64
+}
+
+func (g timedGetter) GetByHeight(ctx context.Context, height uint64) (*header.ExtendedHeader, error) {
+	if height < g.pruned {
+		return nil, header.ErrPruned
+	}
+	h, err := g.getterStub.GetByHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	h.Time = g.now.Add(-g.interval * time.Duration(g.head-height))
+	return h, nil
+}
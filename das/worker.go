@@ -25,6 +25,8 @@ type workerState struct {
 	Curr   uint64
 	Err    error
 	failed []uint64
+	// pruned are the heights skipped as their headers were pruned, so they were not sampled
+	pruned []uint64
 }
 
 // job represents headers interval to be processed by worker
@@ -32,6 +34,8 @@ type job struct {
 	id   int
 	From uint64
 	To   uint64
+	// retries keeps the amount of failed attempts of the previously failed heights within the interval
+	retries map[uint64]int
 }
 
 func (w *worker) run(
@@ -52,6 +56,12 @@ func (w *worker) run(
 				// sampling worker will resume upon restart
 				break
 			}
+			if errors.Is(err, header.ErrPruned) {
+				// the header is out of the store's retention window, so there is nothing to sample
+				log.Debugw("skipping pruned header", "height", curr)
+				w.setPruned(curr)
+				continue
+			}
 			w.setResult(curr, err)
 			log.Errorw("failed to get header from header store", "height", curr,
 				"finished (s)", time.Since(startGet))
@@ -63,7 +73,7 @@ func (w *worker) run(
 			"square width", len(h.DAH.RowsRoots), "data root", h.DAH.Hash(), "finished (s)", time.Since(startGet))
 
 		startSample := time.Now()
-		err = sample(ctx, h)
+		err = sample(ctx, h, w.state.retries[curr])
 		if errors.Is(err, context.Canceled) {
 			// sampling worker will resume upon restart
 			break
@@ -84,6 +94,7 @@ func (w *worker) run(
 	case resultCh <- result{
 		job:    w.state.job,
 		failed: w.state.failed,
+		pruned: w.state.pruned,
 		err:    w.state.Err,
 	}:
 	case <-ctx.Done():
@@ -110,6 +121,14 @@ func (w *worker) setResult(curr uint64, err error) {
 	w.state.Curr = curr
 }
 
+// setPruned records the height skipped without sampling, as its header was pruned.
+func (w *worker) setPruned(curr uint64) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.state.pruned = append(w.state.pruned, curr)
+	w.state.Curr = curr
+}
+
 func (w *worker) getState() workerState {
 	w.lock.Lock()
 	defer w.lock.Unlock()
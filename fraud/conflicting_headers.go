@@ -0,0 +1,107 @@
+package fraud
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	pb "github.com/celestiaorg/celestia-node/fraud/pb"
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+func init() {
+	Register(&ConflictingHeadersProof{})
+}
+
+// ConflictingHeadersProof proves that validators signed two different headers at the same height.
+type ConflictingHeadersProof struct {
+	HeaderA *header.ExtendedHeader
+	HeaderB *header.ExtendedHeader
+}
+
+// CreateConflictingHeadersProof creates a new Conflicting Headers Fraud Proof that should be propagated
+// through network.
+func CreateConflictingHeadersProof(a, b *header.ExtendedHeader) Proof {
+	return &ConflictingHeadersProof{
+		HeaderA: a,
+		HeaderB: b,
+	}
+}
+
+// Type returns type of fraud proof.
+func (p *ConflictingHeadersProof) Type() ProofType {
+	return ConflictingHeaders
+}
+
+// HeaderHash returns the hash of the second header, as the first one is usually known to peers.
+func (p *ConflictingHeadersProof) HeaderHash() []byte {
+	return p.HeaderB.Hash()
+}
+
+// Height returns the height of the conflicting headers.
+func (p *ConflictingHeadersProof) Height() uint64 {
+	return uint64(p.HeaderA.Height)
+}
+
+// MarshalBinary converts ConflictingHeadersProof to binary.
+func (p *ConflictingHeadersProof) MarshalBinary() ([]byte, error) {
+	a, err := p.HeaderA.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	b, err := p.HeaderB.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	proof := pb.ConflictingHeaders{
+		HeaderA: a,
+		HeaderB: b,
+	}
+	return proof.Marshal()
+}
+
+// UnmarshalBinary converts binary to ConflictingHeadersProof.
+func (p *ConflictingHeadersProof) UnmarshalBinary(data []byte) error {
+	in := pb.ConflictingHeaders{}
+	if err := in.Unmarshal(data); err != nil {
+		return err
+	}
+
+	proof := &ConflictingHeadersProof{
+		HeaderA: new(header.ExtendedHeader),
+		HeaderB: new(header.ExtendedHeader),
+	}
+	if err := proof.HeaderA.UnmarshalBinary(in.HeaderA); err != nil {
+		return err
+	}
+	if err := proof.HeaderB.UnmarshalBinary(in.HeaderB); err != nil {
+		return err
+	}
+
+	*p = *proof
+	return nil
+}
+
+// Validate ensures that fraud proof is correct.
+// Validate checks that both headers are valid, differ and are signed
+// by the validator set of the locally known header at the same height.
+func (p *ConflictingHeadersProof) Validate(eh *header.ExtendedHeader) error {
+	if p.HeaderA.Height != eh.Height || p.HeaderB.Height != eh.Height {
+		return errors.New("fraud: incorrect block height")
+	}
+	if bytes.Equal(p.HeaderA.Hash(), p.HeaderB.Hash()) {
+		return errors.New("fraud: headers do not conflict")
+	}
+
+	for _, h := range []*header.ExtendedHeader{p.HeaderA, p.HeaderB} {
+		if !bytes.Equal(h.ValidatorsHash, eh.ValidatorsHash) {
+			return fmt.Errorf("fraud: header %X is signed by unknown validators", h.Hash())
+		}
+		// ensures the header is signed by its validator set
+		if err := h.ValidateBasic(); err != nil {
+			return fmt.Errorf("fraud: invalid header %X: %w", h.Hash(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package fraud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+func TestConflictingHeadersProofValidation(t *testing.T) {
+	suite := header.NewTestSuite(t, 3)
+	headers := suite.GenExtendedHeaders(3)
+	h := headers[1]
+	conflicting := suite.GenConflictingExtendedHeader(h)
+
+	p := CreateConflictingHeadersProof(h, conflicting)
+	require.NoError(t, p.Validate(h))
+
+	bin, err := p.MarshalBinary()
+	require.NoError(t, err)
+	got, err := Unmarshal(ConflictingHeaders, bin)
+	require.NoError(t, err)
+	assert.Equal(t, p.Height(), got.Height())
+	assert.Equal(t, p.HeaderHash(), got.HeaderHash())
+	require.NoError(t, got.Validate(h))
+
+	// the same header twice is not a conflict
+	p = CreateConflictingHeadersProof(h, h)
+	require.Error(t, p.Validate(h))
+	// headers must be at the height of the local header
+	p = CreateConflictingHeadersProof(h, conflicting)
+	require.Error(t, p.Validate(headers[2]))
+}
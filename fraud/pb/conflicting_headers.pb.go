@@ -0,0 +1,370 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: fraud/pb/conflicting_headers.proto
+
+package fraud_pb
+
+import (
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+type ConflictingHeaders struct {
+	HeaderA []byte `protobuf:"bytes,1,opt,name=HeaderA,proto3" json:"HeaderA,omitempty"`
+	HeaderB []byte `protobuf:"bytes,2,opt,name=HeaderB,proto3" json:"HeaderB,omitempty"`
+}
+
+func (m *ConflictingHeaders) Reset()         { *m = ConflictingHeaders{} }
+func (m *ConflictingHeaders) String() string { return proto.CompactTextString(m) }
+func (*ConflictingHeaders) ProtoMessage()    {}
+func (*ConflictingHeaders) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7ec460126c118de3, []int{0}
+}
+func (m *ConflictingHeaders) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ConflictingHeaders) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ConflictingHeaders.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ConflictingHeaders) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConflictingHeaders.Merge(m, src)
+}
+func (m *ConflictingHeaders) XXX_Size() int {
+	return m.Size()
+}
+func (m *ConflictingHeaders) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConflictingHeaders.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConflictingHeaders proto.InternalMessageInfo
+
+func (m *ConflictingHeaders) GetHeaderA() []byte {
+	if m != nil {
+		return m.HeaderA
+	}
+	return nil
+}
+
+func (m *ConflictingHeaders) GetHeaderB() []byte {
+	if m != nil {
+		return m.HeaderB
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ConflictingHeaders)(nil), "fraud.pb.ConflictingHeaders")
+}
+
+func init() {
+	proto.RegisterFile("fraud/pb/conflicting_headers.proto", fileDescriptor_7ec460126c118de3)
+}
+
+var fileDescriptor_7ec460126c118de3 = []byte{
+	// 130 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x4a, 0x2b, 0x4a, 0x2c,
+	0x4d, 0xd1, 0x2f, 0x48, 0xd2, 0x4f, 0xce, 0xcf, 0x4b, 0xcb, 0xc9, 0x4c, 0x2e, 0xc9, 0xcc, 0x4b,
+	0x8f, 0xcf, 0x48, 0x4d, 0x4c, 0x49, 0x2d, 0x2a, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2,
+	0x00, 0xab, 0xd1, 0x2b, 0x48, 0x52, 0xf2, 0xe0, 0x12, 0x72, 0x46, 0x28, 0xf3, 0x80, 0xa8, 0x12,
+	0x92, 0xe0, 0x62, 0x87, 0x30, 0x1d, 0x25, 0x18, 0x15, 0x18, 0x35, 0x78, 0x82, 0x60, 0x5c, 0x84,
+	0x8c, 0x93, 0x04, 0x13, 0xb2, 0x8c, 0x93, 0x93, 0xc4, 0x89, 0x47, 0x72, 0x8c, 0x17, 0x1e, 0xc9,
+	0x31, 0x3e, 0x78, 0x24, 0xc7, 0x38, 0xe1, 0xb1, 0x1c, 0xc3, 0x85, 0xc7, 0x72, 0x0c, 0x37, 0x1e,
+	0xcb, 0x31, 0x24, 0xb1, 0x81, 0x2d, 0x35, 0x06, 0x0c, 0x00, 0xce, 0x1d, 0x1d, 0xba, 0x9a, 0x00,
+	0x00, 0x00,
+}
+
+func (m *ConflictingHeaders) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ConflictingHeaders) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ConflictingHeaders) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.HeaderB) > 0 {
+		i -= len(m.HeaderB)
+		copy(dAtA[i:], m.HeaderB)
+		i = encodeVarintConflictingHeaders(dAtA, i, uint64(len(m.HeaderB)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.HeaderA) > 0 {
+		i -= len(m.HeaderA)
+		copy(dAtA[i:], m.HeaderA)
+		i = encodeVarintConflictingHeaders(dAtA, i, uint64(len(m.HeaderA)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintConflictingHeaders(dAtA []byte, offset int, v uint64) int {
+	offset -= sovConflictingHeaders(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *ConflictingHeaders) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.HeaderA)
+	if l > 0 {
+		n += 1 + l + sovConflictingHeaders(uint64(l))
+	}
+	l = len(m.HeaderB)
+	if l > 0 {
+		n += 1 + l + sovConflictingHeaders(uint64(l))
+	}
+	return n
+}
+
+func sovConflictingHeaders(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozConflictingHeaders(x uint64) (n int) {
+	return sovConflictingHeaders(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *ConflictingHeaders) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConflictingHeaders
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ConflictingHeaders: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ConflictingHeaders: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeaderA", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConflictingHeaders
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthConflictingHeaders
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConflictingHeaders
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HeaderA = append(m.HeaderA[:0], dAtA[iNdEx:postIndex]...)
+			if m.HeaderA == nil {
+				m.HeaderA = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeaderB", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConflictingHeaders
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthConflictingHeaders
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConflictingHeaders
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HeaderB = append(m.HeaderB[:0], dAtA[iNdEx:postIndex]...)
+			if m.HeaderB == nil {
+				m.HeaderB = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConflictingHeaders(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthConflictingHeaders
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipConflictingHeaders(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowConflictingHeaders
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowConflictingHeaders
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowConflictingHeaders
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthConflictingHeaders
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupConflictingHeaders
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthConflictingHeaders
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthConflictingHeaders        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowConflictingHeaders          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupConflictingHeaders = fmt.Errorf("proto: unexpected end of group")
+)
@@ -35,7 +35,8 @@ func (e *errNoUnmarshaler) Error() string {
 type ProofType string
 
 const (
-	BadEncoding ProofType = "badencoding"
+	BadEncoding        ProofType = "badencoding"
+	ConflictingHeaders ProofType = "conflictingheaders"
 )
 
 // Proof is a generic interface that will be used for all types of fraud proofs in the network.
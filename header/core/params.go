@@ -0,0 +1,45 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/celestiaorg/celestia-node/core"
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+// ParamsGetter derives the NetworkParams from Core: the trusting period from the unbonding time
+// of the staking module and the block time from the latest blocks.
+type ParamsGetter struct {
+	fetcher *core.BlockFetcher
+}
+
+func NewParamsGetter(fetcher *core.BlockFetcher) *ParamsGetter {
+	return &ParamsGetter{fetcher: fetcher}
+}
+
+// NetworkParams queries Core for the current NetworkParams.
+func (pg *ParamsGetter) NetworkParams(ctx context.Context) (*header.NetworkParams, error) {
+	unbonding, err := pg.fetcher.UnbondingTime(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting unbonding time: %w", err)
+	}
+	blockTime, err := pg.fetcher.BlockTime(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting block time: %w", err)
+	}
+
+	params := &header.NetworkParams{
+		TrustingPeriod: TrustingPeriod(unbonding),
+		BlockTime:      blockTime,
+	}
+	return params, params.Validate()
+}
+
+// TrustingPeriod derives the trusting period from the unbonding period of the network.
+// It leaves a third of the unbonding period to check headers and to report and punish
+// the misbehaviour, as recommended for light clients.
+func TrustingPeriod(unbonding time.Duration) time.Duration {
+	return unbonding * 2 / 3
+}
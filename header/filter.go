@@ -0,0 +1,118 @@
+package header
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/celestiaorg/nmt"
+	"github.com/celestiaorg/nmt/namespace"
+)
+
+// Filter is a predicate choosing the ExtendedHeaders of interest.
+type Filter func(*ExtendedHeader) bool
+
+// NamespaceRange chooses the headers with data in the given inclusive range of namespaces, i.e.
+// those having a row root with the range of namespaces overlapping the given one.
+// The check is done by the row roots only, so a chosen header may still have no data in the range.
+func NamespaceRange(from, to namespace.ID) Filter {
+	return func(eh *ExtendedHeader) bool {
+		if eh.DAH == nil {
+			return false
+		}
+		for _, row := range eh.DAH.RowsRoots {
+			if !to.Less(nmt.MinNamespace(row, to.Size())) && from.LessOrEqual(nmt.MaxNamespace(row, from.Size())) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// WithNamespace chooses the headers which may have data of the given namespace.
+func WithNamespace(nID namespace.ID) Filter {
+	return NamespaceRange(nID, nID)
+}
+
+// Empty chooses the headers of empty blocks, i.e. with the EmptyDAH.
+func Empty() Filter {
+	return isEmpty
+}
+
+// NonEmpty chooses the headers of blocks with any data, i.e. with a DAH other than the EmptyDAH.
+func NonEmpty() Filter {
+	return func(eh *ExtendedHeader) bool {
+		return !isEmpty(eh)
+	}
+}
+
+// MinSquareSize chooses the headers of blocks with the original data square of at least the given size.
+func MinSquareSize(size int) Filter {
+	return func(eh *ExtendedHeader) bool {
+		// the square of the DAH is extended twice
+		return eh.DAH != nil && len(eh.DAH.RowsRoots)/2 >= size
+	}
+}
+
+// MatchAll chooses the headers matching all the given Filters.
+func MatchAll(filters ...Filter) Filter {
+	return func(eh *ExtendedHeader) bool {
+		for _, filter := range filters {
+			if !filter(eh) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+var (
+	emptyHashOnce sync.Once
+	emptyHash     []byte
+)
+
+// isEmpty checks whether the header is of an empty block.
+func isEmpty(eh *ExtendedHeader) bool {
+	emptyHashOnce.Do(func() {
+		dah := EmptyDAH()
+		emptyHash = dah.Hash()
+	})
+	return eh.DAH != nil && bytes.Equal(eh.DAH.Hash(), emptyHash)
+}
+
+// filteredSubscription is a Subscription delivering only the headers matching its Filters.
+type filteredSubscription struct {
+	Subscription
+	filter Filter
+}
+
+// NewFilteredSubscription wraps the Subscription to skip the headers not matching all the given Filters.
+func NewFilteredSubscription(sub Subscription, filters ...Filter) Subscription {
+	return &filteredSubscription{
+		Subscription: sub,
+		filter:       MatchAll(filters...),
+	}
+}
+
+// SubscribeFiltered creates a new Subscription with the Subscriber,
+// delivering only the headers matching all the given Filters.
+func SubscribeFiltered(sub Subscriber, filters ...Filter) (Subscription, error) {
+	s, err := sub.Subscribe()
+	if err != nil {
+		return nil, err
+	}
+	return NewFilteredSubscription(s, filters...), nil
+}
+
+// NextHeader returns the next header matching the Filters.
+func (fs *filteredSubscription) NextHeader(ctx context.Context) (*ExtendedHeader, error) {
+	for {
+		eh, err := fs.Subscription.NextHeader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if fs.filter(eh) {
+			return eh, nil
+		}
+	}
+}
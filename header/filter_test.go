@@ -0,0 +1,69 @@
+package header
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/celestia-app/pkg/da"
+	"github.com/celestiaorg/nmt"
+	"github.com/celestiaorg/nmt/namespace"
+
+	"github.com/celestiaorg/celestia-node/share"
+)
+
+func TestFilters(t *testing.T) {
+	suite := NewTestSuite(t, 3)
+	empty := suite.GenExtendedHeader()
+	dah := da.NewDataAvailabilityHeader(share.RandEDS(t, 4))
+	full := suite.GenExtendedHeaderWithDAH(&dah)
+
+	first := namespace.ID(nmt.MinNamespace(dah.RowsRoots[0], share.NamespaceSize))
+	last := namespace.ID(nmt.MaxNamespace(dah.RowsRoots[3], share.NamespaceSize))
+	absent := namespace.ID{0, 0, 0, 0, 0, 0, 0, 0}
+
+	var tests = []struct {
+		name        string
+		filter      Filter
+		empty, full bool
+	}{
+		{"empty", Empty(), true, false},
+		{"non-empty", NonEmpty(), false, true},
+		{"min square size", MinSquareSize(4), false, true},
+		{"min square size of empty", MinSquareSize(1), true, true},
+		{"namespace", WithNamespace(first), false, true},
+		{"absent namespace", WithNamespace(absent), false, false},
+		{"namespace range", NamespaceRange(absent, first), false, true},
+		{"all", MatchAll(NonEmpty(), WithNamespace(last)), false, true},
+		{"none", MatchAll(Empty(), MinSquareSize(2)), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.empty, tt.filter(empty))
+			assert.Equal(t, tt.full, tt.filter(full))
+		})
+	}
+}
+
+func TestFilteredSubscription(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	suite := NewTestSuite(t, 3)
+	headers := suite.GenExtendedHeaders(5)
+	dah := da.NewDataAvailabilityHeader(share.RandEDS(t, 2))
+	headers = append(headers, suite.GenExtendedHeaderWithDAH(&dah))
+	headers = append(headers, suite.GenExtendedHeaders(5)...)
+
+	sub, err := SubscribeFiltered(&DummySubscriber{Headers: headers}, NonEmpty())
+	require.NoError(t, err)
+
+	h, err := sub.NextHeader(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, headers[5], h)
+	// the rest are skipped
+	_, err = sub.NextHeader(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
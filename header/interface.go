@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ipfs/go-blockservice"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
@@ -60,6 +61,19 @@ type Exchange interface {
 	Getter
 }
 
+// InvalidReporter is an Exchange able to punish the peers serving headers failing verification.
+type InvalidReporter interface {
+	// ReportInvalid reports the header received from the Exchange as failed verification.
+	ReportInvalid(*ExtendedHeader)
+}
+
+// QuorumExchange is an Exchange able to request the head agreed on by a quorum of trusted peers.
+type QuorumExchange interface {
+	// HeadWithQuorum returns the head agreed on by at least the given amount of trusted peers
+	// or ErrNoQuorum otherwise.
+	HeadWithQuorum(ctx context.Context, quorum int) (*ExtendedHeader, error)
+}
+
 var (
 	// ErrNotFound is returned when there is no requested header.
 	ErrNotFound = errors.New("header: not found")
@@ -67,9 +81,20 @@ var (
 	// ErrNoHead is returned when Store is empty (does not contain any known header).
 	ErrNoHead = fmt.Errorf("header/store: no chain head")
 
+	// ErrPruned is returned when the requested header was stored, but got removed
+	// by the Store's retention policy.
+	ErrPruned = errors.New("header/store: pruned")
+
 	// ErrHeadersLimitExceeded is returned when ExchangeServer receives header request for more
 	// than maxRequestSize headers.
 	ErrHeadersLimitExceeded = errors.New("header/p2p: header limit per 1 request exceeded")
+
+	// ErrRateLimited is returned when ExchangeServer rejects a request due to the peer exceeding
+	// its rate limits or due to too many requests being handled at the moment.
+	ErrRateLimited = errors.New("header/p2p: request rate limited")
+
+	// ErrNoQuorum is returned when not enough peers agree on the same head.
+	ErrNoQuorum = errors.New("header/p2p: no quorum of peers agreed on the head")
 )
 
 // ErrNonAdjacent is returned when Store is appended with a header not adjacent to the stored head.
@@ -101,6 +126,9 @@ type Store interface {
 	// Height reports current height of the chain head.
 	Height() uint64
 
+	// Tail returns the lowest ExtendedHeader kept by the Store.
+	Tail(context.Context) (*ExtendedHeader, error)
+
 	// Has checks whether ExtendedHeader is already stored.
 	Has(context.Context, tmbytes.HexBytes) (bool, error)
 
@@ -110,6 +138,57 @@ type Store interface {
 	// It returns the amount of successfully applied headers,
 	// so caller can understand what given header was invalid, if any.
 	Append(context.Context, ...*ExtendedHeader) (int, error)
+
+	// Prepend stores and verifies the given ExtendedHeader(s) below the current tail.
+	// It requires them to be adjacent and in ascending order, with the last one
+	// preceding the tail, as they are verified by the hash chain going down from the tail.
+	// It returns the amount of successfully applied headers counting from the last one,
+	// so caller can understand what given header was invalid, if any.
+	Prepend(context.Context, ...*ExtendedHeader) (int, error)
+
+	// GetByTime returns the ExtendedHeader chosen by the given time according to the TimeLookup.
+	// It returns ErrNotFound if there is no such header in the Store.
+	GetByTime(context.Context, time.Time, TimeLookup) (*ExtendedHeader, error)
+
+	// GetByDataHash returns the ExtendedHeader with the given DataHash, i.e. the hash of its DAH.
+	// Empty blocks share the same DataHash, so any of their headers can be returned for it.
+	GetByDataHash(context.Context, tmbytes.HexBytes) (*ExtendedHeader, error)
+}
+
+// TimeLookup defines how an ExtendedHeader is chosen by time.
+type TimeLookup int
+
+const (
+	// TimeExact chooses the ExtendedHeader with exactly the given time.
+	TimeExact TimeLookup = iota
+	// TimeFloor chooses the latest ExtendedHeader with time not after the given one.
+	TimeFloor
+	// TimeCeiling chooses the earliest ExtendedHeader with time not before the given one.
+	TimeCeiling
+)
+
+// String returns the name of the TimeLookup.
+func (tl TimeLookup) String() string {
+	switch tl {
+	case TimeExact:
+		return "exact"
+	case TimeFloor:
+		return "floor"
+	case TimeCeiling:
+		return "ceiling"
+	default:
+		return fmt.Sprintf("TimeLookup(%d)", int(tl))
+	}
+}
+
+// ParseTimeLookup parses the TimeLookup from its name.
+func ParseTimeLookup(s string) (TimeLookup, error) {
+	for _, tl := range []TimeLookup{TimeExact, TimeFloor, TimeCeiling} {
+		if tl.String() == s {
+			return tl, nil
+		}
+	}
+	return 0, fmt.Errorf("header: unknown time lookup: %s", s)
 }
 
 // Getter contains the behavior necessary for a component to retrieve
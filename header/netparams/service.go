@@ -0,0 +1,235 @@
+// Package netparams keeps the parameters of the network headers are synced and verified with
+// up to date.
+package netparams
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	logging "github.com/ipfs/go-log/v2"
+
+	"github.com/celestiaorg/celestia-node/header"
+	"github.com/celestiaorg/celestia-node/params"
+)
+
+var log = logging.Logger("header/netparams")
+
+var (
+	// DefaultRefreshInterval is the default interval the network parameters are refreshed at.
+	DefaultRefreshInterval = time.Hour
+	// DefaultFetchTimeout is the default time to wait for the network parameters on every fetch.
+	DefaultFetchTimeout = time.Second * 10
+)
+
+var paramsKey = datastore.NewKey("header_network_params")
+
+// ErrUnknownParams is returned when the parameters of the network are not known yet.
+var ErrUnknownParams = errors.New("header/netparams: network params are unknown")
+
+// DefaultParams returns the NetworkParams used until the ones of the network are known.
+func DefaultParams() header.NetworkParams {
+	return header.NetworkParams{
+		TrustingPeriod: header.TrustingPeriod,
+		BlockTime:      params.BlockTime,
+	}
+}
+
+// Service gets the NetworkParams from the network, caches them in the datastore
+// and refreshes them periodically, so that changes of the network parameters are picked up.
+// Until they are got, the cached or the default ones are used.
+type Service struct {
+	getter header.ParamsGetter
+	ds     datastore.Datastore
+
+	refreshInterval time.Duration
+	fetchTimeout    time.Duration
+
+	paramsLk sync.RWMutex
+	params   header.NetworkParams
+	// known is set once the parameters are got from the network, either now or before
+	known bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Option configures optional Service behaviour.
+type Option func(*Service)
+
+// WithRefreshInterval sets the interval the network parameters are refreshed at.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(s *Service) {
+		s.refreshInterval = interval
+	}
+}
+
+// WithFetchTimeout sets the time to wait for the network parameters on every fetch.
+func WithFetchTimeout(timeout time.Duration) Option {
+	return func(s *Service) {
+		s.fetchTimeout = timeout
+	}
+}
+
+// NewService creates a new Service getting the NetworkParams with the given ParamsGetter.
+func NewService(getter header.ParamsGetter, ds datastore.Datastore, opts ...Option) *Service {
+	s := &Service{
+		getter:          getter,
+		ds:              ds,
+		refreshInterval: DefaultRefreshInterval,
+		fetchTimeout:    DefaultFetchTimeout,
+		params:          DefaultParams(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Start loads the cached network parameters and starts getting and refreshing them in the background,
+// so the start is not blocked by the network. Until they are got, the cached ones are used,
+// or the default ones, if none are cached.
+func (s *Service) Start(ctx context.Context) error {
+	cached, err := s.load(ctx)
+	if err != nil {
+		return err
+	}
+	if cached != nil {
+		s.set(*cached)
+	}
+
+	ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan struct{})
+	go s.refreshLoop(ctx, cached != nil)
+	return nil
+}
+
+// Stop stops refreshing of the network parameters.
+func (s *Service) Stop(ctx context.Context) error {
+	s.cancel()
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// NetworkParams returns the current NetworkParams.
+// Until they are got from the network, the default ones are returned along with ErrUnknownParams.
+func (s *Service) NetworkParams(context.Context) (*header.NetworkParams, error) {
+	s.paramsLk.RLock()
+	defer s.paramsLk.RUnlock()
+	p := s.params
+	if !s.known {
+		return &p, ErrUnknownParams
+	}
+	return &p, nil
+}
+
+// BlockTime returns the current block time of the network.
+func (s *Service) BlockTime() time.Duration {
+	s.paramsLk.RLock()
+	defer s.paramsLk.RUnlock()
+	return s.params.BlockTime
+}
+
+// TrustingPeriod returns the current trusting period of the network.
+func (s *Service) TrustingPeriod() time.Duration {
+	s.paramsLk.RLock()
+	defer s.paramsLk.RUnlock()
+	return s.params.TrustingPeriod
+}
+
+// refreshLoop gets the network parameters right away and then refreshes them periodically.
+func (s *Service) refreshLoop(ctx context.Context, cached bool) {
+	defer close(s.done)
+	err := s.refresh(ctx)
+	if err != nil && ctx.Err() == nil {
+		p, _ := s.NetworkParams(ctx)
+		log.Warnw("getting network params, using cached or default ones",
+			"cached", cached, "trusting_period", p.TrustingPeriod, "block_time", p.BlockTime, "err", err)
+	}
+
+	ticker := time.NewTicker(s.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			err := s.refresh(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Errorw("refreshing network params", "err", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// refresh gets the network parameters and, if they changed, applies and caches them.
+func (s *Service) refresh(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.fetchTimeout)
+	defer cancel()
+	p, err := s.getter.NetworkParams(ctx)
+	if err != nil {
+		return err
+	}
+	err = p.Validate()
+	if err != nil {
+		return err
+	}
+
+	s.paramsLk.RLock()
+	changed := !s.known || s.params != *p
+	s.paramsLk.RUnlock()
+	if !changed {
+		return nil
+	}
+
+	log.Infow("network params changed", "trusting_period", p.TrustingPeriod, "block_time", p.BlockTime)
+	s.set(*p)
+	return s.store(ctx, p)
+}
+
+// set applies the given parameters.
+func (s *Service) set(p header.NetworkParams) {
+	s.paramsLk.Lock()
+	s.params = p
+	s.known = true
+	s.paramsLk.Unlock()
+}
+
+// store caches the parameters in the datastore.
+func (s *Service) store(ctx context.Context, p *header.NetworkParams) error {
+	bs, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return s.ds.Put(ctx, paramsKey, bs)
+}
+
+// load loads the parameters cached before, if any.
+func (s *Service) load(ctx context.Context) (*header.NetworkParams, error) {
+	bs, err := s.ds.Get(ctx, paramsKey)
+	switch err {
+	case nil:
+	case datastore.ErrNotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	p := new(header.NetworkParams)
+	err = json.Unmarshal(bs, p)
+	if err != nil {
+		return nil, err
+	}
+	if err = p.Validate(); err != nil {
+		log.Warnw("ignoring invalid cached network params", "err", err)
+		return nil, nil
+	}
+	return p, nil
+}
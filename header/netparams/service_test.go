@@ -0,0 +1,117 @@
+package netparams
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+func TestService(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	getter := &paramsGetter{err: errors.New("unavailable")}
+
+	// unknown params fall back to the default ones
+	serv := NewService(getter, ds)
+	require.NoError(t, serv.Start(ctx))
+	params, err := serv.NetworkParams(ctx)
+	require.ErrorIs(t, err, ErrUnknownParams)
+	assert.Equal(t, DefaultParams(), *params)
+	assert.Equal(t, header.TrustingPeriod, serv.TrustingPeriod())
+	require.NoError(t, serv.Stop(ctx))
+
+	// params of the network are applied and cached
+	first := header.NetworkParams{TrustingPeriod: time.Hour, BlockTime: time.Second}
+	getter.set(&first, nil)
+	serv = NewService(getter, ds, WithRefreshInterval(time.Millisecond*10))
+	require.NoError(t, serv.Start(ctx))
+	require.Eventually(t, func() bool {
+		_, err := serv.NetworkParams(ctx)
+		return err == nil
+	}, time.Second, time.Millisecond*10)
+	params, err = serv.NetworkParams(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first, *params)
+	assert.Equal(t, first.BlockTime, serv.BlockTime())
+	assert.Equal(t, first.TrustingPeriod, serv.TrustingPeriod())
+
+	// changes are picked up on refresh
+	second := header.NetworkParams{TrustingPeriod: time.Hour * 2, BlockTime: time.Second * 2}
+	getter.set(&second, nil)
+	require.Eventually(t, func() bool {
+		return serv.BlockTime() == second.BlockTime
+	}, time.Second, time.Millisecond*10)
+	assert.Equal(t, second.TrustingPeriod, serv.TrustingPeriod())
+	require.NoError(t, serv.Stop(ctx))
+
+	// cached params are used if the network serves invalid ones
+	getter.set(&header.NetworkParams{}, nil)
+	serv = NewService(getter, ds)
+	require.NoError(t, serv.Start(ctx))
+	params, err = serv.NetworkParams(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, second, *params)
+	require.NoError(t, serv.Stop(ctx))
+}
+
+// TestService_StartUnblocked tests that the Service starts with the cached params
+// without waiting for the network to respond.
+func TestService_StartUnblocked(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	cached := header.NetworkParams{TrustingPeriod: time.Hour, BlockTime: time.Second}
+	serv := NewService(&paramsGetter{params: &cached}, ds)
+	require.NoError(t, serv.Start(ctx))
+	require.Eventually(t, func() bool {
+		_, err := serv.NetworkParams(ctx)
+		return err == nil
+	}, time.Second, time.Millisecond*10)
+	require.NoError(t, serv.Stop(ctx))
+
+	serv = NewService(blockingGetter{}, ds, WithFetchTimeout(time.Hour))
+	require.NoError(t, serv.Start(ctx))
+	params, err := serv.NetworkParams(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, cached, *params)
+	// the pending fetch is canceled on stop
+	require.NoError(t, serv.Stop(ctx))
+}
+
+// blockingGetter never responds until the request is canceled.
+type blockingGetter struct{}
+
+func (blockingGetter) NetworkParams(ctx context.Context) (*header.NetworkParams, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+type paramsGetter struct {
+	lk     sync.Mutex
+	params *header.NetworkParams
+	err    error
+}
+
+func (pg *paramsGetter) set(params *header.NetworkParams, err error) {
+	pg.lk.Lock()
+	defer pg.lk.Unlock()
+	pg.params, pg.err = params, err
+}
+
+func (pg *paramsGetter) NetworkParams(context.Context) (*header.NetworkParams, error) {
+	pg.lk.Lock()
+	defer pg.lk.Unlock()
+	return pg.params, pg.err
+}
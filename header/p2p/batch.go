@@ -0,0 +1,257 @@
+package p2p
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+
+	"github.com/celestiaorg/go-libp2p-messenger/serde"
+
+	"github.com/celestiaorg/celestia-node/header"
+	p2p_pb "github.com/celestiaorg/celestia-node/header/p2p/pb"
+	header_pb "github.com/celestiaorg/celestia-node/header/pb"
+)
+
+// maxBatchSize limits the uncompressed size of headers sent within one ExtendedHeaderBatch,
+// so that the batch always fits into a single message.
+var maxBatchSize = int(serde.MaxMessageSize / 2)
+
+// batchEncoder packs ExtendedHeaders of a single response into compact batches.
+// Validator sets already sent within the response are replaced with their hashes.
+type batchEncoder struct {
+	compression p2p_pb.Compression
+
+	sent  map[string]struct{}
+	batch *p2p_pb.CompactHeaders
+	size  int
+}
+
+func newBatchEncoder(compression p2p_pb.Compression) *batchEncoder {
+	return &batchEncoder{
+		compression: compression,
+		sent:        make(map[string]struct{}),
+		batch:       &p2p_pb.CompactHeaders{},
+	}
+}
+
+// add appends the header to the current batch.
+// It reports whether the batch reached its size limit and must be flushed.
+func (be *batchEncoder) add(h *header.ExtendedHeader) (bool, error) {
+	pb, err := header.ExtendedHeaderToProto(h)
+	if err != nil {
+		return false, err
+	}
+
+	compact := &p2p_pb.CompactHeader{}
+	valHash := h.ValidatorSet.Hash()
+	if _, ok := be.sent[string(valHash)]; ok {
+		pb.ValidatorSet = nil
+		compact.ValidatorSetHash = valHash
+	} else {
+		be.sent[string(valHash)] = struct{}{}
+	}
+
+	compact.Header, err = pb.Marshal()
+	if err != nil {
+		return false, err
+	}
+
+	be.batch.Headers = append(be.batch.Headers, compact)
+	be.size += compact.Size()
+	return be.size >= maxBatchSize, nil
+}
+
+// flush returns the ExtendedHeaderBatch with headers added since the last flush.
+func (be *batchEncoder) flush() (*p2p_pb.ExtendedHeaderBatch, error) {
+	body, err := be.batch.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	be.batch, be.size = &p2p_pb.CompactHeaders{}, 0
+
+	body, err = compress(be.compression, body)
+	if err != nil {
+		return nil, err
+	}
+	return &p2p_pb.ExtendedHeaderBatch{
+		StatusCode:  p2p_pb.StatusCode_OK,
+		Compression: be.compression,
+		Body:        body,
+	}, nil
+}
+
+// batchDecoder unpacks ExtendedHeaders from batches of a single response.
+type batchDecoder struct {
+	valSets map[string]*tmproto.ValidatorSet
+}
+
+func newBatchDecoder() *batchDecoder {
+	return &batchDecoder{
+		valSets: make(map[string]*tmproto.ValidatorSet),
+	}
+}
+
+// decode unpacks the batch resolving references to validator sets received before.
+func (bd *batchDecoder) decode(batch *p2p_pb.ExtendedHeaderBatch) ([]*header.ExtendedHeader, error) {
+	body, err := decompress(batch.Compression, batch.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	compacts := &p2p_pb.CompactHeaders{}
+	err = compacts.Unmarshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make([]*header.ExtendedHeader, len(compacts.Headers))
+	for i, compact := range compacts.Headers {
+		pb := &header_pb.ExtendedHeader{}
+		err = pb.Unmarshal(compact.Header)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(compact.ValidatorSetHash) != 0 {
+			valSet, ok := bd.valSets[string(compact.ValidatorSetHash)]
+			if !ok {
+				return nil, fmt.Errorf("reference to unknown validator set %X", compact.ValidatorSetHash)
+			}
+			pb.ValidatorSet = valSet
+		}
+
+		// ProtoToExtendedHeader ensures the validator set matches the header's ValidatorsHash,
+		// so references can't substitute the set
+		headers[i], err = header.ProtoToExtendedHeader(pb)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(compact.ValidatorSetHash) == 0 {
+			bd.valSets[string(headers[i].ValidatorSet.Hash())] = pb.ValidatorSet
+		}
+	}
+	return headers, nil
+}
+
+// writeBatches writes the headers to the stream in ExtendedHeaderBatches.
+func writeBatches(
+	stream network.Stream,
+	headers []*header.ExtendedHeader,
+	compression p2p_pb.Compression,
+) error {
+	write := func(batch *p2p_pb.ExtendedHeaderBatch) error {
+		if err := stream.SetWriteDeadline(time.Now().Add(writeDeadline)); err != nil {
+			log.Debugf("error setting deadline: %s", err)
+		}
+		_, err := serde.Write(stream, batch)
+		return err
+	}
+
+	enc := newBatchEncoder(compression)
+	for i, h := range headers {
+		full, err := enc.add(h)
+		if err != nil {
+			return err
+		}
+		if !full && i != len(headers)-1 {
+			continue
+		}
+
+		batch, err := enc.flush()
+		if err != nil {
+			return err
+		}
+		err = write(batch)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readBatches reads the requested amount of headers from ExtendedHeaderBatches in the stream.
+func readBatches(stream network.Stream, amount uint64) ([]*header.ExtendedHeader, error) {
+	dec := newBatchDecoder()
+	headers := make([]*header.ExtendedHeader, 0, amount)
+	for uint64(len(headers)) < amount {
+		if err := stream.SetReadDeadline(time.Now().Add(readDeadline)); err != nil {
+			log.Debugf("error setting deadline: %s", err)
+		}
+		batch := new(p2p_pb.ExtendedHeaderBatch)
+		n, err := serde.Read(stream, batch)
+		if err != nil {
+			// the peer might not have all the requested headers, so it ends the stream early
+			if n == 0 && errors.Is(err, io.EOF) && len(headers) != 0 {
+				return headers, nil
+			}
+			return nil, err
+		}
+
+		if err = convertStatusCodeToError(batch.StatusCode); err != nil {
+			return nil, err
+		}
+
+		hs, err := dec.decode(batch)
+		if err != nil {
+			return nil, err
+		}
+		if len(hs) == 0 || uint64(len(headers)+len(hs)) > amount {
+			return nil, fmt.Errorf("unexpected amount of headers in batch: %d", len(hs))
+		}
+		headers = append(headers, hs...)
+	}
+	return headers, nil
+}
+
+// compress compresses the data with the given compression.
+func compress(compression p2p_pb.Compression, data []byte) ([]byte, error) {
+	switch compression {
+	case p2p_pb.Compression_NONE:
+		return data, nil
+	case p2p_pb.Compression_GZIP:
+		buf := new(bytes.Buffer)
+		w := gzip.NewWriter(buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unknown compression %d", compression)
+	}
+}
+
+// decompress decompresses the data compressed with the given compression.
+// The decompressed size is limited, so a malicious peer can't exhaust the memory.
+func decompress(compression p2p_pb.Compression, data []byte) ([]byte, error) {
+	switch compression {
+	case p2p_pb.Compression_NONE:
+		return data, nil
+	case p2p_pb.Compression_GZIP:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		data, err = io.ReadAll(io.LimitReader(r, int64(serde.MaxMessageSize)+1))
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(data)) > serde.MaxMessageSize {
+			return nil, fmt.Errorf("decompressed batch exceeds %d bytes", serde.MaxMessageSize)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unknown compression %d", compression)
+	}
+}
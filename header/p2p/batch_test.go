@@ -0,0 +1,62 @@
+package p2p
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/celestia-node/header"
+	p2p_pb "github.com/celestiaorg/celestia-node/header/p2p/pb"
+)
+
+func TestBatchEncoding(t *testing.T) {
+	suite := header.NewTestSuite(t, 3)
+	headers := suite.GenExtendedHeaders(10)
+
+	for _, compression := range []p2p_pb.Compression{p2p_pb.Compression_NONE, p2p_pb.Compression_GZIP} {
+		t.Run(compression.String(), func(t *testing.T) {
+			enc := newBatchEncoder(compression)
+			for _, h := range headers {
+				full, err := enc.add(h)
+				require.NoError(t, err)
+				require.False(t, full)
+			}
+			// the validator set is unchanged, so it is sent only once
+			for i, compact := range enc.batch.Headers {
+				assert.Equal(t, i != 0, len(compact.ValidatorSetHash) != 0)
+			}
+
+			batch, err := enc.flush()
+			require.NoError(t, err)
+			assert.Equal(t, compression, batch.Compression)
+			assert.Empty(t, enc.batch.Headers)
+
+			got, err := newBatchDecoder().decode(batch)
+			require.NoError(t, err)
+			require.Len(t, got, len(headers))
+			for i := range headers {
+				assert.Equal(t, headers[i].Hash(), got[i].Hash())
+				assert.Equal(t, headers[i].ValidatorSet.Hash(), got[i].ValidatorSet.Hash())
+			}
+		})
+	}
+}
+
+func TestBatchDecoding_UnknownValidatorSet(t *testing.T) {
+	suite := header.NewTestSuite(t, 3)
+	headers := suite.GenExtendedHeaders(2)
+
+	enc := newBatchEncoder(p2p_pb.Compression_GZIP)
+	for _, h := range headers {
+		_, err := enc.add(h)
+		require.NoError(t, err)
+	}
+	// drop the header carrying the validator set, so the reference can't be resolved
+	enc.batch.Headers = enc.batch.Headers[1:]
+
+	batch, err := enc.flush()
+	require.NoError(t, err)
+	_, err = newBatchDecoder().decode(batch)
+	require.Error(t, err)
+}
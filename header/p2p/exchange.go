@@ -3,16 +3,20 @@ package p2p
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"math/rand"
+	"io"
 	"sort"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru"
 	logging "github.com/ipfs/go-log/v2"
 	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/protocol"
 	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/celestiaorg/go-libp2p-messenger/serde"
 
@@ -32,6 +36,9 @@ const (
 	minResponses = 2
 	// requestSize defines the max amount of headers that can be requested/handled at once.
 	maxRequestSize uint64 = 512
+	// sourcesCacheSize is the amount of the latest received headers whose source peers are remembered,
+	// so that the peers can be punished once the headers fail verification.
+	sourcesCacheSize = 4096
 )
 
 // PubSubTopic hardcodes the name of the ExtendedHeader
@@ -40,25 +47,66 @@ const PubSubTopic = "header-sub"
 
 var exchangeProtocolID = protocol.ID(fmt.Sprintf("/header-ex/v0.0.3/%s", params.DefaultNetwork()))
 
+// exchangeProtocolIDv2 is the version of the protocol sending headers in compact batches.
+// It is preferred over exchangeProtocolID, which is kept for compatibility with older peers.
+var exchangeProtocolIDv2 = protocol.ID(fmt.Sprintf("/header-ex/v2.0.0/%s", params.DefaultNetwork()))
+
 // Exchange enables sending outbound ExtendedHeaderRequests to the network as well as
 // handling inbound ExtendedHeaderRequests from the network.
+// Besides the trusted peers, it tracks any connected peer supporting the protocol
+// and routes requests to the best scored ones.
 type Exchange struct {
 	host host.Host
 
 	trustedPeers peer.IDSlice
+	peerTracker  *peerTracker
+	// sources maps the hashes of the received headers to the peers they were received from
+	sources *lru.Cache
 }
 
 func NewExchange(host host.Host, peers peer.IDSlice) *Exchange {
+	// the cache can only fail to be created with a non-positive size
+	sources, _ := lru.New(sourcesCacheSize)
 	return &Exchange{
 		host:         host,
-		trustedPeers: peers,
+		trustedPeers: uniquePeers(peers),
+		peerTracker:  newPeerTracker(host),
+		sources:      sources,
 	}
 }
 
-// Head requests the latest ExtendedHeader. Note that the ExtendedHeader
-// must be verified thereafter.
+// Start starts tracking of the peers serving headers.
+func (ex *Exchange) Start(context.Context) error {
+	return ex.peerTracker.start()
+}
+
+// Stop stops tracking of the peers.
+func (ex *Exchange) Stop(ctx context.Context) error {
+	return ex.peerTracker.stop(ctx)
+}
+
+// Head requests the latest ExtendedHeader from the trusted peers only, as it is used
+// for subjective initialization. Note that the ExtendedHeader must be verified thereafter.
 func (ex *Exchange) Head(ctx context.Context) (*header.ExtendedHeader, error) {
 	log.Debug("requesting head")
+	return ex.head(ctx, 0)
+}
+
+// HeadWithQuorum requests the latest ExtendedHeader from the trusted peers, like Head, but only accepts it
+// once at least the given amount of distinct trusted peers agree on it, for strict subjective initialization.
+// Otherwise, it fails with header.ErrNoQuorum.
+func (ex *Exchange) HeadWithQuorum(ctx context.Context, quorum int) (*header.ExtendedHeader, error) {
+	log.Debugw("requesting head with quorum", "quorum", quorum)
+	if quorum > len(ex.trustedPeers) {
+		return nil, fmt.Errorf("%w: quorum of %d exceeds the amount of trusted peers %d",
+			header.ErrNoQuorum, quorum, len(ex.trustedPeers))
+	}
+	return ex.head(ctx, quorum)
+}
+
+// head requests the latest ExtendedHeader from the trusted peers,
+// choosing the one agreed on by the quorum of them, if set, or the best one otherwise.
+func (ex *Exchange) head(ctx context.Context, quorum int) (*header.ExtendedHeader, error) {
 	// create request
 	req := &p2p_pb.ExtendedHeaderRequest{
 		Data:   &p2p_pb.ExtendedHeaderRequest_Origin{Origin: uint64(0)},
@@ -92,6 +140,9 @@ func (ex *Exchange) Head(ctx context.Context) (*header.ExtendedHeader, error) {
 		}
 	}
 
+	if quorum > 0 {
+		return quorumHead(result, quorum)
+	}
 	return bestHead(result)
 }
 
@@ -117,9 +168,14 @@ func (ex *Exchange) GetByHeight(ctx context.Context, height uint64) (*header.Ext
 }
 
 // GetRangeByHeight performs a request for the given range of ExtendedHeaders
-// to the network. Note that the ExtendedHeaders must be verified thereafter.
+// to the network. Ranges bigger than maxRequestSize are split into chunks,
+// which are requested from multiple peers in parallel.
+// Note that the ExtendedHeaders must be verified thereafter.
 func (ex *Exchange) GetRangeByHeight(ctx context.Context, from, amount uint64) ([]*header.ExtendedHeader, error) {
 	log.Debugw("requesting headers", "from", from, "to", from+amount)
+	if amount > maxRequestSize {
+		return ex.performChunkedRequest(ctx, from, amount)
+	}
 	// create request
 	req := &p2p_pb.ExtendedHeaderRequest{
 		Data:   &p2p_pb.ExtendedHeaderRequest_Origin{Origin: from},
@@ -156,13 +212,158 @@ func (ex *Exchange) performRequest(
 		return make([]*header.ExtendedHeader, 0), nil
 	}
 
-	if len(ex.trustedPeers) == 0 {
-		return nil, fmt.Errorf("no trusted peers")
+	peers := ex.peers()
+	if len(peers) == 0 {
+		return nil, fmt.Errorf("no peers to request headers from")
+	}
+
+	return ex.requestWithRetry(ctx, req, peers, 0)
+}
+
+// performChunkedRequest splits the given range into chunks of maxRequestSize
+// and requests them in parallel from different peers, reassembling the results in order.
+func (ex *Exchange) performChunkedRequest(
+	ctx context.Context,
+	from, amount uint64,
+) ([]*header.ExtendedHeader, error) {
+	peers := ex.peers()
+	if len(peers) == 0 {
+		return nil, fmt.Errorf("no peers to request headers from")
+	}
+
+	chunks := (amount + maxRequestSize - 1) / maxRequestSize
+	results := make([][]*header.ExtendedHeader, chunks)
+
+	errg, ctx := errgroup.WithContext(ctx)
+	// there is no point to have more requests in flight than peers to serve them
+	errg.SetLimit(len(peers))
+	for i := uint64(0); i < chunks; i++ {
+		i := i
+		origin, size := from+i*maxRequestSize, maxRequestSize
+		if left := from + amount - origin; left < size {
+			size = left
+		}
+
+		req := &p2p_pb.ExtendedHeaderRequest{
+			Data:   &p2p_pb.ExtendedHeaderRequest_Origin{Origin: origin},
+			Amount: size,
+		}
+		last := i == chunks-1
+		errg.Go(func() (err error) {
+			// spread chunks over the peers, starting from the best ones
+			results[i], err = ex.requestWithRetry(ctx, req, peers, int(i))
+			if err != nil {
+				return err
+			}
+			// only the last chunk may be cut short, otherwise the range would have a gap
+			if !last && uint64(len(results[i])) != req.Amount {
+				return fmt.Errorf("incomplete range: requested %d headers from %d, got %d",
+					req.Amount, req.GetOrigin(), len(results[i]))
+			}
+			return nil
+		})
+	}
+	if err := errg.Wait(); err != nil {
+		return nil, err
+	}
+
+	headers := make([]*header.ExtendedHeader, 0, amount)
+	for _, res := range results {
+		headers = append(headers, res...)
+	}
+	// ensure the chunks joined into a contiguous range
+	for i, h := range headers {
+		if uint64(h.Height) != from+uint64(i) {
+			return nil, fmt.Errorf("unexpected header height: expected %d, got %d", from+uint64(i), h.Height)
+		}
+	}
+	return headers, nil
+}
+
+// peers returns the peers to request headers from ordered by preference:
+// the tracked peers sorted by their score followed by the remaining trusted peers.
+func (ex *Exchange) peers() peer.IDSlice {
+	peers := ex.peerTracker.bestPeers()
+	tracked := make(map[peer.ID]struct{}, len(peers))
+	for _, p := range peers {
+		tracked[p] = struct{}{}
+	}
+
+	for _, p := range ex.trustedPeers {
+		if _, ok := tracked[p]; !ok {
+			peers = append(peers, p)
+		}
 	}
+	return peers
+}
 
-	//nolint:gosec // G404: Use of weak random number generator
-	index := rand.Intn(len(ex.trustedPeers))
-	return request(ctx, ex.trustedPeers[index], ex.host, req)
+// requestWithRetry sends the request to the given peers one by one,
+// beginning from the peer at the given index, until any of them responds successfully.
+// The outcome of every attempt is recorded by the peer tracker.
+func (ex *Exchange) requestWithRetry(
+	ctx context.Context,
+	req *p2p_pb.ExtendedHeaderRequest,
+	peers peer.IDSlice,
+	index int,
+) (headers []*header.ExtendedHeader, err error) {
+	for i := 0; i < len(peers); i++ {
+		to := peers[(index+i)%len(peers)]
+		start := time.Now()
+		headers, err = request(ctx, to, ex.host, req)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err == nil {
+			err = validateRange(req, headers)
+			if err == nil {
+				ex.peerTracker.success(to, time.Since(start))
+				for _, h := range headers {
+					ex.sources.Add(h.Hash().String(), to)
+				}
+				return headers, nil
+			}
+			ex.peerTracker.invalid(to)
+		} else {
+			ex.peerTracker.failure(to)
+		}
+
+		log.Debugw("request failed, retrying with another peer", "peer", to, "err", err)
+	}
+	return nil, err
+}
+
+// ReportInvalid punishes the peer the given header was received from, as the header failed verification,
+// so that the peer is not preferred for the requests anymore.
+// Headers not received by the Exchange recently are ignored.
+func (ex *Exchange) ReportInvalid(h *header.ExtendedHeader) {
+	hash := h.Hash().String()
+	from, ok := ex.sources.Get(hash)
+	if !ok {
+		return
+	}
+	ex.sources.Remove(hash)
+	log.Warnw("peer served invalid header", "peer", from, "height", h.Height, "hash", hash)
+	ex.peerTracker.invalid(from.(peer.ID))
+}
+
+// validateRange ensures the headers are exactly the ones requested by origin.
+// The range may be cut short in the end, if the peer does not have all the requested headers yet.
+func validateRange(req *p2p_pb.ExtendedHeaderRequest, headers []*header.ExtendedHeader) error {
+	if uint64(len(headers)) > req.Amount {
+		return fmt.Errorf("unexpected amount of headers: requested %d, got %d", req.Amount, len(headers))
+	}
+	origin := req.GetOrigin()
+	// head requests and requests by hash can't be checked against the origin
+	if origin == 0 {
+		return nil
+	}
+
+	for i, h := range headers {
+		if uint64(h.Height) != origin+uint64(i) {
+			return fmt.Errorf("unexpected header height: expected %d, got %d", origin+uint64(i), h.Height)
+		}
+	}
+	return nil
 }
 
 // request sends the ExtendedHeaderRequest to a remote peer.
@@ -172,13 +373,22 @@ func request(
 	host host.Host,
 	req *p2p_pb.ExtendedHeaderRequest,
 ) ([]*header.ExtendedHeader, error) {
-	stream, err := host.NewStream(ctx, to, exchangeProtocolID)
+	stream, err := host.NewStream(ctx, to, exchangeProtocolIDv2, exchangeProtocolID)
 	if err != nil {
 		return nil, err
 	}
 	if err = stream.SetWriteDeadline(time.Now().Add(writeDeadline)); err != nil {
 		log.Debugf("error setting deadline: %s", err)
 	}
+	v2 := stream.Protocol() == exchangeProtocolIDv2
+	if v2 && req.Amount > 1 {
+		// a single header has nothing to deduplicate, so compression pays off for ranges only
+		req = &p2p_pb.ExtendedHeaderRequest{
+			Data:        req.Data,
+			Amount:      req.Amount,
+			Compression: p2p_pb.Compression_GZIP,
+		}
+	}
 	// send request
 	_, err = serde.Write(stream, req)
 	if err != nil {
@@ -190,37 +400,53 @@ func request(
 		log.Error(err)
 	}
 	// read responses
-	headers := make([]*header.ExtendedHeader, req.Amount)
-	for i := 0; i < int(req.Amount); i++ {
+	var headers []*header.ExtendedHeader
+	if v2 {
+		headers, err = readBatches(stream, req.Amount)
+	} else {
+		headers, err = readResponses(stream, req.Amount)
+	}
+	if err != nil {
+		stream.Reset() //nolint:errcheck
+		return nil, err
+	}
+	if err = stream.Close(); err != nil {
+		log.Errorw("closing stream", "err", err)
+	}
+	// ensure at least one header was retrieved
+	if len(headers) == 0 {
+		return nil, header.ErrNotFound
+	}
+	return headers, nil
+}
+
+// readResponses reads the requested amount of headers from ExtendedHeaderResponses in the stream.
+func readResponses(stream network.Stream, amount uint64) ([]*header.ExtendedHeader, error) {
+	headers := make([]*header.ExtendedHeader, amount)
+	for i := 0; i < int(amount); i++ {
 		resp := new(p2p_pb.ExtendedHeaderResponse)
-		if err = stream.SetReadDeadline(time.Now().Add(readDeadline)); err != nil {
+		if err := stream.SetReadDeadline(time.Now().Add(readDeadline)); err != nil {
 			log.Debugf("error setting deadline: %s", err)
 		}
-		_, err := serde.Read(stream, resp)
+		n, err := serde.Read(stream, resp)
 		if err != nil {
-			stream.Reset() //nolint:errcheck
+			// the peer might not have all the requested headers, so it ends the stream early
+			if n == 0 && errors.Is(err, io.EOF) && i != 0 {
+				return headers[:i], nil
+			}
 			return nil, err
 		}
 
 		if err = convertStatusCodeToError(resp.StatusCode); err != nil {
-			stream.Reset() //nolint:errcheck
 			return nil, err
 		}
 		header, err := header.UnmarshalExtendedHeader(resp.Body)
 		if err != nil {
-			stream.Reset() //nolint:errcheck
 			return nil, err
 		}
 
 		headers[i] = header
 	}
-	if err = stream.Close(); err != nil {
-		log.Errorw("closing stream", "err", err)
-	}
-	// ensure at least one header was retrieved
-	if len(headers) == 0 {
-		return nil, header.ErrNotFound
-	}
 	return headers, nil
 }
 
@@ -253,6 +479,40 @@ func bestHead(result []*header.ExtendedHeader) (*header.ExtendedHeader, error) {
 	return result[0], nil
 }
 
+// quorumHead chooses the ExtendedHeader with the max height among those received
+// at least from the quorum of peers. It fails with header.ErrNoQuorum if there is no such header.
+func quorumHead(result []*header.ExtendedHeader, quorum int) (*header.ExtendedHeader, error) {
+	counter := make(map[string]int)
+	for _, res := range result {
+		counter[res.Hash().String()]++
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Height > result[j].Height
+	})
+
+	for _, res := range result {
+		if counter[res.Hash().String()] >= quorum {
+			return res, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no head was reported by at least %d of %d responded peers",
+		header.ErrNoQuorum, quorum, len(result))
+}
+
+// uniquePeers removes duplicates from the given peers, so that every peer counts once.
+func uniquePeers(peers peer.IDSlice) peer.IDSlice {
+	seen := make(map[peer.ID]struct{}, len(peers))
+	unique := make(peer.IDSlice, 0, len(peers))
+	for _, p := range peers {
+		if _, ok := seen[p]; ok {
+			continue
+		}
+		seen[p] = struct{}{}
+		unique = append(unique, p)
+	}
+	return unique
+}
+
 // convertStatusCodeToError converts passed status code into an error.
 func convertStatusCodeToError(code p2p_pb.StatusCode) error {
 	switch code {
@@ -262,6 +522,8 @@ func convertStatusCodeToError(code p2p_pb.StatusCode) error {
 		return header.ErrNotFound
 	case p2p_pb.StatusCode_LIMIT_EXCEEDED:
 		return header.ErrHeadersLimitExceeded
+	case p2p_pb.StatusCode_RATE_LIMITED:
+		return header.ErrRateLimited
 	default:
 		return fmt.Errorf("unknown status code %d", code)
 	}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"testing"
+	"time"
 
 	libhost "github.com/libp2p/go-libp2p-core/host"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -67,7 +68,7 @@ func TestExchange_RequestHeadersFails(t *testing.T) {
 		},
 		{
 			amount:      600,
-			expectedErr: &header.ErrHeadersLimitExceeded,
+			expectedErr: &header.ErrNotFound,
 		},
 	}
 	for _, test := range tt {
@@ -80,13 +81,149 @@ func TestExchange_RequestHeadersFails(t *testing.T) {
 
 func TestExchange_RequestHeadersLimitExceed(t *testing.T) {
 	host, peer := createMocknet(t)
-	exchg, _ := createP2PExAndServer(t, host, peer)
-	// perform expected request
-	_, err := exchg.GetRangeByHeight(context.Background(), 1, 600)
+	_, _ = createP2PExAndServer(t, host, peer)
+	// perform request bypassing chunking on the Exchange side
+	req := &p2p_pb.ExtendedHeaderRequest{
+		Data:   &p2p_pb.ExtendedHeaderRequest_Origin{Origin: 1},
+		Amount: 600,
+	}
+	_, err := request(context.Background(), peer.ID(), host, req)
 	require.Error(t, err)
 	require.ErrorAs(t, err, &header.ErrHeadersLimitExceeded)
 }
 
+// TestExchange_RequestPrunedHeaders tests that the pruned headers are reported as not found
+// instead of failing the request.
+func TestExchange_RequestPrunedHeaders(t *testing.T) {
+	host, peer := createMocknet(t)
+	serv := NewExchangeServer(peer, &prunedStore{createStore(t, 5)})
+	err := serv.Start(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		serv.Stop(context.Background()) //nolint:errcheck
+	})
+
+	req := &p2p_pb.ExtendedHeaderRequest{
+		Data:   &p2p_pb.ExtendedHeaderRequest_Origin{Origin: 1},
+		Amount: 5,
+	}
+	_, err = request(context.Background(), peer.ID(), host, req)
+	require.ErrorIs(t, err, header.ErrNotFound)
+}
+
+// TestExchange_RequestHeadersFromLegacyPeer tests that the Exchange falls back to
+// the previous protocol version for peers not supporting the batched one.
+func TestExchange_RequestHeadersFromLegacyPeer(t *testing.T) {
+	host, peer := createMocknet(t)
+	exchg, store := createP2PExAndServer(t, host, peer)
+	// the peer serves only the previous protocol version
+	peer.RemoveStreamHandler(exchangeProtocolIDv2)
+
+	gotHeaders, err := exchg.GetRangeByHeight(context.Background(), 1, 5)
+	require.NoError(t, err)
+	require.Len(t, gotHeaders, 5)
+	for _, got := range gotHeaders {
+		assert.Equal(t, store.headers[got.Height].Hash(), got.Hash())
+	}
+
+	_, err = exchg.GetRangeByHeight(context.Background(), 1, 10)
+	require.ErrorIs(t, err, header.ErrNotFound)
+}
+
+// TestExchange_RequestHeadersFromMultiplePeers tests that the Exchange splits big ranges into chunks,
+// requests them from multiple peers and retries chunks failed on one peer with another.
+func TestExchange_RequestHeadersFromMultiplePeers(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(cancel)
+
+	net, err := mocknet.FullMeshConnected(4)
+	require.NoError(t, err)
+	hosts := net.Hosts()
+
+	suite := header.NewTestSuite(t, 3)
+	store := &mockStore{headers: make(map[int64]*header.ExtendedHeader)}
+	_, err = store.Append(ctx, suite.GenExtendedHeaders(1200)...)
+	require.NoError(t, err)
+
+	// the last peer does not serve headers at all
+	peers := make([]peer.ID, 0, len(hosts)-1)
+	for _, h := range hosts[1:] {
+		peers = append(peers, h.ID())
+		if h == hosts[len(hosts)-1] {
+			continue
+		}
+
+		serv := NewExchangeServer(h, store)
+		err = serv.Start(ctx)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			serv.Stop(context.Background()) //nolint:errcheck
+		})
+	}
+
+	exchg := NewExchange(hosts[0], peers)
+	gotHeaders, err := exchg.GetRangeByHeight(ctx, 1, 1100)
+	require.NoError(t, err)
+	require.Len(t, gotHeaders, 1100)
+	for i, got := range gotHeaders {
+		assert.EqualValues(t, i+1, got.Height)
+		assert.Equal(t, store.headers[got.Height].Hash(), got.Hash())
+	}
+}
+
+// TestExchange_RequestPartialRange tests that the Exchange accepts ranges cut short in the end
+// by peers not having all the requested headers, but never ranges with gaps.
+func TestExchange_RequestPartialRange(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+	store := &partialStore{&mockStore{headers: make(map[int64]*header.ExtendedHeader)}}
+	_, err := store.Append(ctx, suite.GenExtendedHeaders(1100)...)
+	require.NoError(t, err)
+
+	newExchange := func(legacy bool) *Exchange {
+		host, tpeer := createMocknet(t)
+		serv := NewExchangeServer(tpeer, store)
+		err := serv.Start(ctx)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			serv.Stop(context.Background()) //nolint:errcheck
+		})
+		if legacy {
+			tpeer.RemoveStreamHandler(exchangeProtocolIDv2)
+			// wait for the peer to announce the protocols it supports
+			require.Eventually(t, func() bool {
+				protos, err := host.Peerstore().SupportsProtocols(tpeer.ID(),
+					string(exchangeProtocolIDv2), string(exchangeProtocolID))
+				return err == nil && len(protos) == 1 && protos[0] == string(exchangeProtocolID)
+			}, time.Second*5, time.Millisecond*10)
+		}
+		return NewExchange(host, []peer.ID{tpeer.ID()})
+	}
+	requireRange := func(exchg *Exchange, from, amount, expected uint64) {
+		gotHeaders, err := exchg.GetRangeByHeight(ctx, from, amount)
+		require.NoError(t, err)
+		require.Len(t, gotHeaders, int(expected))
+		for i, got := range gotHeaders {
+			assert.EqualValues(t, from+uint64(i), got.Height)
+		}
+	}
+
+	exchg := newExchange(false)
+	requireRange(exchg, 1090, 20, 11)
+	// the last chunk is cut short
+	requireRange(exchg, 1, 1110, 1100)
+	// the previous protocol version cuts the range short as well
+	requireRange(newExchange(true), 1090, 20, 11)
+
+	// a chunk in the middle is cut short by the gap
+	delete(store.headers, 700)
+	_, err = exchg.GetRangeByHeight(ctx, 1, 1100)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "incomplete range")
+}
+
 // TestExchange_RequestByHash tests that the Exchange instance can
 // respond to an ExtendedHeaderRequest for a hash instead of a height.
 func TestExchange_RequestByHash(t *testing.T) {
@@ -195,6 +332,65 @@ func Test_bestHead(t *testing.T) {
 	}
 }
 
+func Test_quorumHead(t *testing.T) {
+	suite := header.NewTestSuite(t, 3)
+	res := suite.GenExtendedHeaders(3)
+	// the highest header is reported by a single peer only
+	res = append(res, res[0], res[1])
+
+	head, err := quorumHead(res, 2)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, head.Height)
+
+	_, err = quorumHead(res, 3)
+	assert.ErrorIs(t, err, header.ErrNoQuorum)
+}
+
+// TestExchange_RequestHeadWithQuorum tests that the head is only accepted
+// once the quorum of trusted peers agree on it.
+func TestExchange_RequestHeadWithQuorum(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	t.Cleanup(cancel)
+
+	net, err := mocknet.FullMeshConnected(4)
+	require.NoError(t, err)
+	hosts := net.Hosts()
+
+	honest, eclipsing := createStore(t, 5), createStore(t, 7)
+	peers := make([]peer.ID, 0, len(hosts)-1)
+	for i, h := range hosts[1:] {
+		peers = append(peers, h.ID())
+		store := honest
+		if i == len(hosts)-2 {
+			store = eclipsing
+		}
+
+		serv := NewExchangeServer(h, store)
+		err = serv.Start(ctx)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			serv.Stop(context.Background()) //nolint:errcheck
+		})
+	}
+
+	// duplicated peers don't count towards the quorum
+	exchg := NewExchange(hosts[0], append(peers, peers[0]))
+	head, err := exchg.HeadWithQuorum(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, honest.headers[honest.headHeight].Hash(), head.Hash())
+
+	_, err = exchg.HeadWithQuorum(ctx, 3)
+	assert.ErrorIs(t, err, header.ErrNoQuorum)
+
+	_, err = exchg.HeadWithQuorum(ctx, 4)
+	assert.ErrorIs(t, err, header.ErrNoQuorum)
+
+	// the head requested without quorum is not affected
+	head, err = exchg.Head(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, honest.headers[honest.headHeight].Hash(), head.Hash())
+}
+
 // TestExchange_RequestByHashFails tests that the Exchange instance can
 // respond with a StatusCode_NOT_FOUND if it will not have requested header.
 func TestExchange_RequestByHashFails(t *testing.T) {
@@ -228,6 +424,53 @@ func TestExchange_RequestByHashFails(t *testing.T) {
 	require.Equal(t, resp.StatusCode, p2p_pb.StatusCode_NOT_FOUND)
 }
 
+func TestExchange_RequestNetworkParams(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	net, err := mocknet.FullMeshConnected(3)
+	require.NoError(t, err)
+	host, unknown, known := net.Hosts()[0], net.Hosts()[1], net.Hosts()[2]
+
+	expected := &header.NetworkParams{TrustingPeriod: time.Hour, BlockTime: time.Second}
+	for _, serv := range []*ExchangeServer{
+		NewExchangeServer(unknown, createStore(t, 0), WithParamsGetter(&paramsGetter{err: header.ErrNotFound})),
+		NewExchangeServer(known, createStore(t, 0), WithParamsGetter(&paramsGetter{params: expected})),
+	} {
+		serv := serv
+		require.NoError(t, serv.Start(ctx))
+		t.Cleanup(func() {
+			serv.Stop(context.Background()) //nolint:errcheck
+		})
+	}
+
+	// the peer not knowing the params is skipped
+	ex := NewExchange(host, []peer.ID{unknown.ID(), known.ID()})
+	params, err := ex.NetworkParams(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, expected, params)
+
+	ex = NewExchange(host, []peer.ID{unknown.ID()})
+	_, err = ex.NetworkParams(ctx)
+	require.ErrorIs(t, err, errNoParams)
+
+	// the trusting period is not extended beyond the local one
+	expected.TrustingPeriod = header.TrustingPeriod * 10
+	ex = NewExchange(host, []peer.ID{known.ID()})
+	params, err = ex.NetworkParams(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, header.TrustingPeriod, params.TrustingPeriod)
+}
+
+type paramsGetter struct {
+	params *header.NetworkParams
+	err    error
+}
+
+func (pg *paramsGetter) NetworkParams(context.Context) (*header.NetworkParams, error) {
+	return pg.params, pg.err
+}
+
 func createMocknet(t *testing.T) (libhost.Host, libhost.Host) {
 	net, err := mocknet.FullMeshConnected(2)
 	require.NoError(t, err)
@@ -287,6 +530,10 @@ func (m *mockStore) Head(context.Context) (*header.ExtendedHeader, error) {
 	return m.headers[m.headHeight], nil
 }
 
+func (m *mockStore) Tail(context.Context) (*header.ExtendedHeader, error) {
+	return m.headers[1], nil
+}
+
 func (m *mockStore) Get(ctx context.Context, hash tmbytes.HexBytes) (*header.ExtendedHeader, error) {
 	for _, header := range m.headers {
 		if bytes.Equal(header.Hash(), hash) {
@@ -315,10 +562,25 @@ func (m *mockStore) GetRangeByHeight(ctx context.Context, from, to uint64) ([]*h
 	return headers, nil
 }
 
+func (m *mockStore) GetByTime(context.Context, time.Time, header.TimeLookup) (*header.ExtendedHeader, error) {
+	return nil, header.ErrNotFound
+}
+
+func (m *mockStore) GetByDataHash(context.Context, tmbytes.HexBytes) (*header.ExtendedHeader, error) {
+	return nil, header.ErrNotFound
+}
+
 func (m *mockStore) Has(context.Context, tmbytes.HexBytes) (bool, error) {
 	return false, nil
 }
 
+func (m *mockStore) Prepend(ctx context.Context, headers ...*header.ExtendedHeader) (int, error) {
+	for _, header := range headers {
+		m.headers[header.Height] = header
+	}
+	return len(headers), nil
+}
+
 func (m *mockStore) Append(ctx context.Context, headers ...*header.ExtendedHeader) (int, error) {
 	for _, header := range headers {
 		m.headers[header.Height] = header
@@ -329,3 +591,32 @@ func (m *mockStore) Append(ctx context.Context, headers ...*header.ExtendedHeade
 	}
 	return len(headers), nil
 }
+
+// partialStore serves ranges up to the first missing header instead of failing.
+type partialStore struct {
+	*mockStore
+}
+
+func (p *partialStore) GetRangeByHeight(ctx context.Context, from, to uint64) ([]*header.ExtendedHeader, error) {
+	headers := make([]*header.ExtendedHeader, 0, to-from)
+	for ; from < to; from++ {
+		h, ok := p.headers[int64(from)]
+		if !ok {
+			break
+		}
+		headers = append(headers, h)
+	}
+	if len(headers) == 0 {
+		return nil, header.ErrNotFound
+	}
+	return headers, nil
+}
+
+// prunedStore has all the headers pruned.
+type prunedStore struct {
+	*mockStore
+}
+
+func (p *prunedStore) GetRangeByHeight(context.Context, uint64, uint64) ([]*header.ExtendedHeader, error) {
+	return nil, header.ErrPruned
+}
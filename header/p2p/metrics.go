@@ -0,0 +1,39 @@
+package p2p
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+)
+
+var meter = global.MeterProvider().Meter("header/p2p")
+
+type serverMetrics struct {
+	rateLimited syncint64.Counter
+}
+
+// InitMetrics enables Otel metrics to monitor the peers rate limited by the ExchangeServer.
+func (serv *ExchangeServer) InitMetrics() error {
+	rateLimited, err := meter.SyncInt64().Counter("header_p2p_server_rate_limited_counter",
+		instrument.WithDescription("requests rejected due to the rate limits"))
+	if err != nil {
+		return err
+	}
+
+	serv.metrics = &serverMetrics{
+		rateLimited: rateLimited,
+	}
+	return nil
+}
+
+// observeRateLimited records the request rejected for the given reason.
+// The peers are not recorded to keep the cardinality bounded, so they are only logged.
+func (m *serverMetrics) observeRateLimited(ctx context.Context, reason string) {
+	if m == nil {
+		return
+	}
+	m.rateLimited.Add(ctx, 1, attribute.String("reason", reason))
+}
@@ -0,0 +1,132 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+
+	"github.com/celestiaorg/go-libp2p-messenger/serde"
+
+	"github.com/celestiaorg/celestia-node/header"
+	p2p_pb "github.com/celestiaorg/celestia-node/header/p2p/pb"
+	"github.com/celestiaorg/celestia-node/params"
+)
+
+// paramsProtocolID is the protocol serving the parameters of the network the peer syncs headers with.
+var paramsProtocolID = protocol.ID(fmt.Sprintf("/header-params/v0.0.1/%s", params.DefaultNetwork()))
+
+// errNoParams is returned when none of the trusted peers served valid network parameters.
+var errNoParams = errors.New("header/p2p: no trusted peer served network params")
+
+// WithParamsGetter enables serving of the network parameters given by the ParamsGetter,
+// so peers without access to Core can get them.
+func WithParamsGetter(getter header.ParamsGetter) ServerOption {
+	return func(serv *ExchangeServer) {
+		serv.params = getter
+	}
+}
+
+// NetworkParams requests the parameters of the network from the trusted peers,
+// returning the first valid ones received. The trusting period received is clamped to
+// the local header.TrustingPeriod, so a single peer cannot disable the expiration of headers.
+func (ex *Exchange) NetworkParams(ctx context.Context) (*header.NetworkParams, error) {
+	log.Debug("requesting network params")
+	for _, from := range ex.trustedPeers {
+		p, err := ex.requestParams(ctx, from)
+		if err == nil {
+			return p, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Errorw("network params request to trusted peer failed", "trustedPeer", from, "err", err)
+	}
+	return nil, errNoParams
+}
+
+// requestParams requests the network parameters from the given peer.
+func (ex *Exchange) requestParams(ctx context.Context, from peer.ID) (*header.NetworkParams, error) {
+	stream, err := ex.host.NewStream(ctx, from, paramsProtocolID)
+	if err != nil {
+		return nil, err
+	}
+	// the protocol takes no request
+	err = stream.CloseWrite()
+	if err != nil {
+		log.Error(err)
+	}
+	if err = stream.SetReadDeadline(time.Now().Add(readDeadline)); err != nil {
+		log.Debugf("error setting deadline: %s", err)
+	}
+
+	resp := new(p2p_pb.NetworkParamsResponse)
+	_, err = serde.Read(stream, resp)
+	if err != nil {
+		stream.Reset() //nolint:errcheck
+		return nil, err
+	}
+	if err = stream.Close(); err != nil {
+		log.Errorw("closing stream", "err", err)
+	}
+	if err = convertStatusCodeToError(resp.StatusCode); err != nil {
+		return nil, err
+	}
+
+	p := &header.NetworkParams{
+		TrustingPeriod: time.Duration(resp.TrustingPeriod),
+		BlockTime:      time.Duration(resp.BlockTime),
+	}
+	if p.TrustingPeriod > header.TrustingPeriod {
+		log.Warnw("clamping trusting period received from peer",
+			"peer", from, "received", p.TrustingPeriod, "max", header.TrustingPeriod)
+		p.TrustingPeriod = header.TrustingPeriod
+	}
+	return p, p.Validate()
+}
+
+// paramsHandler handles inbound requests for the network parameters.
+func (serv *ExchangeServer) paramsHandler(stream network.Stream) {
+	from := stream.Conn().RemotePeer()
+	// the request serves no headers, so only the request limits apply
+	if reason := serv.limiter.acquire(from, 0); reason != "" {
+		log.Debugw("server: rate limiting peer", "peer", from, "reason", reason)
+		serv.metrics.observeRateLimited(serv.ctx, reason)
+		serv.writeParams(stream, &p2p_pb.NetworkParamsResponse{StatusCode: p2p_pb.StatusCode_RATE_LIMITED})
+		return
+	}
+	defer serv.limiter.release()
+
+	resp := &p2p_pb.NetworkParamsResponse{StatusCode: p2p_pb.StatusCode_NOT_FOUND}
+	p, err := serv.params.NetworkParams(serv.ctx)
+	if err == nil {
+		resp = &p2p_pb.NetworkParamsResponse{
+			StatusCode:     p2p_pb.StatusCode_OK,
+			TrustingPeriod: int64(p.TrustingPeriod),
+			BlockTime:      int64(p.BlockTime),
+		}
+	} else {
+		log.Errorw("server: getting network params", "err", err)
+	}
+	serv.writeParams(stream, resp)
+}
+
+// writeParams writes the network params response to the stream and closes it.
+func (serv *ExchangeServer) writeParams(stream network.Stream, resp *p2p_pb.NetworkParamsResponse) {
+	if err := stream.SetWriteDeadline(time.Now().Add(writeDeadline)); err != nil {
+		log.Debugf("error setting deadline: %s", err)
+	}
+	_, err := serde.Write(stream, resp)
+	if err != nil {
+		log.Errorw("server: writing network params", "err", err)
+		stream.Reset() //nolint:errcheck
+		return
+	}
+	if err = stream.Close(); err != nil {
+		log.Errorw("while closing inbound stream", "err", err)
+	}
+}
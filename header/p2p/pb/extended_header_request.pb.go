@@ -29,6 +29,8 @@ const (
 	StatusCode_OK             StatusCode = 1
 	StatusCode_NOT_FOUND      StatusCode = 2
 	StatusCode_LIMIT_EXCEEDED StatusCode = 3
+	// RATE_LIMITED is returned when the peer exceeds the request rate limits of the server.
+	StatusCode_RATE_LIMITED StatusCode = 4
 )
 
 var StatusCode_name = map[int32]string{
@@ -36,6 +38,7 @@ var StatusCode_name = map[int32]string{
 	1: "OK",
 	2: "NOT_FOUND",
 	3: "LIMIT_EXCEEDED",
+	4: "RATE_LIMITED",
 }
 
 var StatusCode_value = map[string]int32{
@@ -43,6 +46,7 @@ var StatusCode_value = map[string]int32{
 	"OK":             1,
 	"NOT_FOUND":      2,
 	"LIMIT_EXCEEDED": 3,
+	"RATE_LIMITED":   4,
 }
 
 func (x StatusCode) String() string {
@@ -53,12 +57,40 @@ func (StatusCode) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_ea2a1467b965216e, []int{0}
 }
 
+// Compression defines the algorithm used to compress batches of headers.
+type Compression int32
+
+const (
+	Compression_NONE Compression = 0
+	Compression_GZIP Compression = 1
+)
+
+var Compression_name = map[int32]string{
+	0: "NONE",
+	1: "GZIP",
+}
+
+var Compression_value = map[string]int32{
+	"NONE": 0,
+	"GZIP": 1,
+}
+
+func (x Compression) String() string {
+	return proto.EnumName(Compression_name, int32(x))
+}
+
+func (Compression) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_ea2a1467b965216e, []int{1}
+}
+
 type ExtendedHeaderRequest struct {
 	// Types that are valid to be assigned to Data:
 	//	*ExtendedHeaderRequest_Origin
 	//	*ExtendedHeaderRequest_Hash
 	Data   isExtendedHeaderRequest_Data `protobuf_oneof:"data"`
 	Amount uint64                       `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	// compression is the compression requested for the response. Only used by the v2 protocol.
+	Compression Compression `protobuf:"varint,4,opt,name=compression,proto3,enum=p2p.pb.Compression" json:"compression,omitempty"`
 }
 
 func (m *ExtendedHeaderRequest) Reset()         { *m = ExtendedHeaderRequest{} }
@@ -138,6 +170,13 @@ func (m *ExtendedHeaderRequest) GetAmount() uint64 {
 	return 0
 }
 
+func (m *ExtendedHeaderRequest) GetCompression() Compression {
+	if m != nil {
+		return m.Compression
+	}
+	return Compression_NONE
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*ExtendedHeaderRequest) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -198,10 +237,241 @@ func (m *ExtendedHeaderResponse) GetStatusCode() StatusCode {
 	return StatusCode_INVALID
 }
 
+// ExtendedHeaderBatch is a response of the v2 protocol carrying multiple headers at once.
+type ExtendedHeaderBatch struct {
+	StatusCode  StatusCode  `protobuf:"varint,1,opt,name=statusCode,proto3,enum=p2p.pb.StatusCode" json:"statusCode,omitempty"`
+	Compression Compression `protobuf:"varint,2,opt,name=compression,proto3,enum=p2p.pb.Compression" json:"compression,omitempty"`
+	// body is a serialized CompactHeaders compressed with the given compression.
+	Body []byte `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+}
+
+func (m *ExtendedHeaderBatch) Reset()         { *m = ExtendedHeaderBatch{} }
+func (m *ExtendedHeaderBatch) String() string { return proto.CompactTextString(m) }
+func (*ExtendedHeaderBatch) ProtoMessage()    {}
+func (*ExtendedHeaderBatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ea2a1467b965216e, []int{2}
+}
+func (m *ExtendedHeaderBatch) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExtendedHeaderBatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExtendedHeaderBatch.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExtendedHeaderBatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExtendedHeaderBatch.Merge(m, src)
+}
+func (m *ExtendedHeaderBatch) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExtendedHeaderBatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExtendedHeaderBatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExtendedHeaderBatch proto.InternalMessageInfo
+
+func (m *ExtendedHeaderBatch) GetStatusCode() StatusCode {
+	if m != nil {
+		return m.StatusCode
+	}
+	return StatusCode_INVALID
+}
+
+func (m *ExtendedHeaderBatch) GetCompression() Compression {
+	if m != nil {
+		return m.Compression
+	}
+	return Compression_NONE
+}
+
+func (m *ExtendedHeaderBatch) GetBody() []byte {
+	if m != nil {
+		return m.Body
+	}
+	return nil
+}
+
+type CompactHeaders struct {
+	Headers []*CompactHeader `protobuf:"bytes,1,rep,name=headers,proto3" json:"headers,omitempty"`
+}
+
+func (m *CompactHeaders) Reset()         { *m = CompactHeaders{} }
+func (m *CompactHeaders) String() string { return proto.CompactTextString(m) }
+func (*CompactHeaders) ProtoMessage()    {}
+func (*CompactHeaders) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ea2a1467b965216e, []int{3}
+}
+func (m *CompactHeaders) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CompactHeaders) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CompactHeaders.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CompactHeaders) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompactHeaders.Merge(m, src)
+}
+func (m *CompactHeaders) XXX_Size() int {
+	return m.Size()
+}
+func (m *CompactHeaders) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompactHeaders.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompactHeaders proto.InternalMessageInfo
+
+func (m *CompactHeaders) GetHeaders() []*CompactHeader {
+	if m != nil {
+		return m.Headers
+	}
+	return nil
+}
+
+// CompactHeader is an ExtendedHeader which validator set may be replaced with a reference
+// to the validator set of a header sent before within the same response.
+type CompactHeader struct {
+	// header is a serialized ExtendedHeader.
+	Header []byte `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	// validatorSetHash is set instead of the header's validator set, if it was already sent.
+	ValidatorSetHash []byte `protobuf:"bytes,2,opt,name=validatorSetHash,proto3" json:"validatorSetHash,omitempty"`
+}
+
+func (m *CompactHeader) Reset()         { *m = CompactHeader{} }
+func (m *CompactHeader) String() string { return proto.CompactTextString(m) }
+func (*CompactHeader) ProtoMessage()    {}
+func (*CompactHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ea2a1467b965216e, []int{4}
+}
+func (m *CompactHeader) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CompactHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CompactHeader.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CompactHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompactHeader.Merge(m, src)
+}
+func (m *CompactHeader) XXX_Size() int {
+	return m.Size()
+}
+func (m *CompactHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompactHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompactHeader proto.InternalMessageInfo
+
+func (m *CompactHeader) GetHeader() []byte {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *CompactHeader) GetValidatorSetHash() []byte {
+	if m != nil {
+		return m.ValidatorSetHash
+	}
+	return nil
+}
+
+// NetworkParamsResponse carries the parameters of the network the server syncs and verifies headers with.
+// It responds to the params protocol, which takes no request.
+type NetworkParamsResponse struct {
+	StatusCode StatusCode `protobuf:"varint,1,opt,name=statusCode,proto3,enum=p2p.pb.StatusCode" json:"statusCode,omitempty"`
+	// trustingPeriod is in nanoseconds.
+	TrustingPeriod int64 `protobuf:"varint,2,opt,name=trustingPeriod,proto3" json:"trustingPeriod,omitempty"`
+	// blockTime is in nanoseconds.
+	BlockTime int64 `protobuf:"varint,3,opt,name=blockTime,proto3" json:"blockTime,omitempty"`
+}
+
+func (m *NetworkParamsResponse) Reset()         { *m = NetworkParamsResponse{} }
+func (m *NetworkParamsResponse) String() string { return proto.CompactTextString(m) }
+func (*NetworkParamsResponse) ProtoMessage()    {}
+func (*NetworkParamsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ea2a1467b965216e, []int{5}
+}
+func (m *NetworkParamsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NetworkParamsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_NetworkParamsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *NetworkParamsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkParamsResponse.Merge(m, src)
+}
+func (m *NetworkParamsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *NetworkParamsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkParamsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkParamsResponse proto.InternalMessageInfo
+
+func (m *NetworkParamsResponse) GetStatusCode() StatusCode {
+	if m != nil {
+		return m.StatusCode
+	}
+	return StatusCode_INVALID
+}
+
+func (m *NetworkParamsResponse) GetTrustingPeriod() int64 {
+	if m != nil {
+		return m.TrustingPeriod
+	}
+	return 0
+}
+
+func (m *NetworkParamsResponse) GetBlockTime() int64 {
+	if m != nil {
+		return m.BlockTime
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("p2p.pb.StatusCode", StatusCode_name, StatusCode_value)
+	proto.RegisterEnum("p2p.pb.Compression", Compression_name, Compression_value)
 	proto.RegisterType((*ExtendedHeaderRequest)(nil), "p2p.pb.ExtendedHeaderRequest")
 	proto.RegisterType((*ExtendedHeaderResponse)(nil), "p2p.pb.ExtendedHeaderResponse")
+	proto.RegisterType((*ExtendedHeaderBatch)(nil), "p2p.pb.ExtendedHeaderBatch")
+	proto.RegisterType((*CompactHeaders)(nil), "p2p.pb.CompactHeaders")
+	proto.RegisterType((*CompactHeader)(nil), "p2p.pb.CompactHeader")
+	proto.RegisterType((*NetworkParamsResponse)(nil), "p2p.pb.NetworkParamsResponse")
 }
 
 func init() {
@@ -209,26 +479,39 @@ func init() {
 }
 
 var fileDescriptor_ea2a1467b965216e = []byte{
-	// 293 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x90, 0xc1, 0x4a, 0xc3, 0x40,
-	0x10, 0x86, 0xb3, 0x6d, 0x88, 0x38, 0xd6, 0x12, 0x06, 0x2d, 0x39, 0x85, 0xd2, 0x53, 0x51, 0x48,
-	0x21, 0x3e, 0x81, 0x6d, 0x22, 0x0d, 0xd6, 0x16, 0xd6, 0x2a, 0xde, 0xe2, 0x86, 0x5d, 0xda, 0x1e,
-	0xcc, 0xae, 0xd9, 0x2d, 0xe8, 0x5b, 0xf8, 0x58, 0x1e, 0x7b, 0xf4, 0x28, 0xed, 0x8b, 0x88, 0xdb,
-	0xa0, 0xe2, 0x6d, 0xfe, 0xf9, 0x3f, 0xf8, 0x86, 0x81, 0xf3, 0xa5, 0x60, 0x5c, 0x54, 0x03, 0x15,
-	0xab, 0x81, 0x2a, 0x06, 0xe2, 0xc5, 0x88, 0x92, 0x0b, 0x9e, 0xef, 0xd7, 0x79, 0x25, 0x9e, 0xd7,
-	0x42, 0x9b, 0x48, 0x55, 0xd2, 0x48, 0xf4, 0x54, 0xac, 0x22, 0x55, 0xf4, 0x16, 0x70, 0x9a, 0xd6,
-	0xe0, 0xd8, 0x72, 0x74, 0x8f, 0x61, 0x00, 0x9e, 0xac, 0x56, 0x8b, 0x55, 0x19, 0x90, 0x2e, 0xe9,
-	0xbb, 0x63, 0x87, 0xd6, 0x19, 0x4f, 0xc0, 0x5d, 0x32, 0xbd, 0x0c, 0x1a, 0x5d, 0xd2, 0x6f, 0x8d,
-	0x1d, 0x6a, 0x13, 0x76, 0xc0, 0x63, 0x4f, 0x72, 0x5d, 0x9a, 0xa0, 0xf9, 0xcd, 0xd3, 0x3a, 0x0d,
-	0x3d, 0x70, 0x39, 0x33, 0xac, 0xf7, 0x08, 0x9d, 0xff, 0x22, 0xad, 0x64, 0xa9, 0x05, 0x22, 0xb8,
-	0x85, 0xe4, 0xaf, 0xd6, 0xd3, 0xa2, 0x76, 0xc6, 0x18, 0x40, 0x1b, 0x66, 0xd6, 0x7a, 0x24, 0xb9,
-	0xb0, 0xa6, 0x76, 0x8c, 0xd1, 0xfe, 0xe6, 0xe8, 0xf6, 0xa7, 0xa1, 0x7f, 0xa8, 0xb3, 0x04, 0xe0,
-	0xb7, 0xc1, 0x23, 0x38, 0xc8, 0xa6, 0xf7, 0x97, 0x93, 0x2c, 0xf1, 0x1d, 0xf4, 0xa0, 0x31, 0xbb,
-	0xf6, 0x09, 0x1e, 0xc3, 0xe1, 0x74, 0x36, 0xcf, 0xaf, 0x66, 0x77, 0xd3, 0xc4, 0x6f, 0x20, 0x42,
-	0x7b, 0x92, 0xdd, 0x64, 0xf3, 0x3c, 0x7d, 0x18, 0xa5, 0x69, 0x92, 0x26, 0x7e, 0x73, 0x18, 0xbc,
-	0x6f, 0x43, 0xb2, 0xd9, 0x86, 0xe4, 0x73, 0x1b, 0x92, 0xb7, 0x5d, 0xe8, 0x6c, 0x76, 0xa1, 0xf3,
-	0xb1, 0x0b, 0x9d, 0xc2, 0xb3, 0x9f, 0xbb, 0xf8, 0x0a, 0x00, 0x00, 0xff, 0xff, 0xf4, 0x41, 0xe0,
-	0x70, 0x68, 0x01, 0x00, 0x00,
+	// 503 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x93, 0xcb, 0x6e, 0xda, 0x40,
+	0x14, 0x86, 0x3d, 0xd8, 0x72, 0x9a, 0x03, 0x41, 0xd6, 0xa4, 0x44, 0x5e, 0x54, 0x16, 0xf5, 0xa2,
+	0x42, 0x54, 0x02, 0xc9, 0x55, 0x1f, 0x80, 0x8b, 0x5b, 0x50, 0x53, 0x83, 0x06, 0x1a, 0x55, 0xdd,
+	0xd0, 0x01, 0x8f, 0x82, 0x95, 0xe0, 0x71, 0x67, 0x86, 0x5e, 0xde, 0xa2, 0x95, 0xba, 0xee, 0xf3,
+	0x74, 0x99, 0x65, 0x97, 0x15, 0xbc, 0x48, 0x85, 0x4d, 0x0c, 0xa1, 0x9b, 0x28, 0xbb, 0x39, 0xe7,
+	0xff, 0x74, 0x6e, 0xbf, 0x06, 0x9e, 0xcf, 0x19, 0x0d, 0x99, 0x68, 0x26, 0x5e, 0xd2, 0x4c, 0xa6,
+	0x4d, 0xf6, 0x55, 0xb1, 0x38, 0x64, 0xe1, 0x24, 0x4b, 0x4f, 0x04, 0xfb, 0xb4, 0x64, 0x52, 0x35,
+	0x12, 0xc1, 0x15, 0xc7, 0x66, 0xe2, 0x25, 0x8d, 0x64, 0xea, 0xfe, 0x42, 0x50, 0xf1, 0xb7, 0x64,
+	0x2f, 0x05, 0x49, 0xc6, 0x61, 0x1b, 0x4c, 0x2e, 0xa2, 0xcb, 0x28, 0xb6, 0x51, 0x15, 0xd5, 0x8c,
+	0x9e, 0x46, 0xb6, 0x31, 0x7e, 0x0c, 0xc6, 0x9c, 0xca, 0xb9, 0x5d, 0xa8, 0xa2, 0x5a, 0xa9, 0xa7,
+	0x91, 0x34, 0xc2, 0x67, 0x60, 0xd2, 0x05, 0x5f, 0xc6, 0xca, 0xd6, 0x37, 0x3c, 0xd9, 0x46, 0xf8,
+	0x25, 0x14, 0x67, 0x7c, 0x91, 0x08, 0x26, 0x65, 0xc4, 0x63, 0xdb, 0xa8, 0xa2, 0x5a, 0xd9, 0x3b,
+	0x6d, 0x64, 0xfd, 0x1b, 0x9d, 0x9d, 0x44, 0xf6, 0xb9, 0xb6, 0x09, 0x46, 0x48, 0x15, 0x75, 0x3f,
+	0xc2, 0xd9, 0xe1, 0x7c, 0x32, 0xe1, 0xb1, 0x64, 0x18, 0x83, 0x31, 0xe5, 0xe1, 0xb7, 0x74, 0xbc,
+	0x12, 0x49, 0xdf, 0xd8, 0x03, 0x90, 0x8a, 0xaa, 0xa5, 0xec, 0xf0, 0x90, 0xa5, 0x03, 0x96, 0x3d,
+	0x7c, 0xdb, 0x6b, 0x94, 0x2b, 0x64, 0x8f, 0x72, 0x7f, 0x22, 0x38, 0xbd, 0xdb, 0xa2, 0x4d, 0xd5,
+	0x6c, 0x7e, 0x50, 0x0b, 0xdd, 0xa7, 0xd6, 0xe1, 0xb2, 0x85, 0xfb, 0x2d, 0x9b, 0xaf, 0xa2, 0xef,
+	0x56, 0x71, 0x5b, 0x50, 0xde, 0xf0, 0x74, 0xa6, 0xb2, 0xa1, 0x24, 0x6e, 0xc2, 0x51, 0xe6, 0xa5,
+	0xb4, 0x51, 0x55, 0xaf, 0x15, 0xbd, 0xca, 0x7e, 0xe1, 0x1c, 0x24, 0xb7, 0x94, 0x3b, 0x82, 0x93,
+	0x3b, 0xca, 0xc6, 0xa3, 0x4c, 0xdb, 0x1e, 0x6d, 0x1b, 0xe1, 0x3a, 0x58, 0x9f, 0xe9, 0x75, 0x14,
+	0x52, 0xc5, 0xc5, 0x88, 0xa9, 0x5e, 0xee, 0x2e, 0xf9, 0x2f, 0xef, 0xfe, 0x40, 0x50, 0x09, 0x98,
+	0xfa, 0xc2, 0xc5, 0xd5, 0x90, 0x0a, 0xba, 0x90, 0xb9, 0x21, 0x0f, 0x39, 0xd8, 0x33, 0x28, 0x2b,
+	0xb1, 0x94, 0x2a, 0x8a, 0x2f, 0x87, 0x4c, 0x44, 0x3c, 0x4c, 0xfb, 0xea, 0xe4, 0x20, 0x8b, 0x9f,
+	0xc0, 0xf1, 0xf4, 0x9a, 0xcf, 0xae, 0xc6, 0xd1, 0x82, 0xa5, 0x67, 0xd2, 0xc9, 0x2e, 0x51, 0xbf,
+	0x00, 0xd8, 0xd5, 0xc7, 0x45, 0x38, 0xea, 0x07, 0x17, 0xad, 0xf3, 0x7e, 0xd7, 0xd2, 0xb0, 0x09,
+	0x85, 0xc1, 0x1b, 0x0b, 0xe1, 0x13, 0x38, 0x0e, 0x06, 0xe3, 0xc9, 0xab, 0xc1, 0xbb, 0xa0, 0x6b,
+	0x15, 0x30, 0x86, 0xf2, 0x79, 0xff, 0x6d, 0x7f, 0x3c, 0xf1, 0xdf, 0x77, 0x7c, 0xbf, 0xeb, 0x77,
+	0x2d, 0x1d, 0x5b, 0x50, 0x22, 0xad, 0xb1, 0x3f, 0x49, 0x05, 0xbf, 0x6b, 0x19, 0xf5, 0xa7, 0x50,
+	0xdc, 0xf3, 0x0c, 0x3f, 0x02, 0x23, 0x18, 0x04, 0xbe, 0xa5, 0x6d, 0x5e, 0xaf, 0x3f, 0xf4, 0x87,
+	0x16, 0x6a, 0xdb, 0xbf, 0x57, 0x0e, 0xba, 0x59, 0x39, 0xe8, 0xef, 0xca, 0x41, 0xdf, 0xd7, 0x8e,
+	0x76, 0xb3, 0x76, 0xb4, 0x3f, 0x6b, 0x47, 0x9b, 0x9a, 0xe9, 0x4f, 0x7b, 0xf1, 0x6f, 0x00, 0x29,
+	0x38, 0x50, 0xd5, 0x98, 0x03, 0x00, 0x00,
 }
 
 func (m *ExtendedHeaderRequest) Marshal() (dAtA []byte, err error) {
@@ -251,6 +534,11 @@ func (m *ExtendedHeaderRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Compression != 0 {
+		i = encodeVarintExtendedHeaderRequest(dAtA, i, uint64(m.Compression))
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.Amount != 0 {
 		i = encodeVarintExtendedHeaderRequest(dAtA, i, uint64(m.Amount))
 		i--
@@ -331,71 +619,295 @@ func (m *ExtendedHeaderResponse) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintExtendedHeaderRequest(dAtA []byte, offset int, v uint64) int {
-	offset -= sovExtendedHeaderRequest(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *ExtendedHeaderBatch) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *ExtendedHeaderRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *ExtendedHeaderBatch) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExtendedHeaderBatch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Data != nil {
-		n += m.Data.Size()
+	if len(m.Body) > 0 {
+		i -= len(m.Body)
+		copy(dAtA[i:], m.Body)
+		i = encodeVarintExtendedHeaderRequest(dAtA, i, uint64(len(m.Body)))
+		i--
+		dAtA[i] = 0x1a
 	}
-	if m.Amount != 0 {
-		n += 1 + sovExtendedHeaderRequest(uint64(m.Amount))
+	if m.Compression != 0 {
+		i = encodeVarintExtendedHeaderRequest(dAtA, i, uint64(m.Compression))
+		i--
+		dAtA[i] = 0x10
 	}
-	return n
+	if m.StatusCode != 0 {
+		i = encodeVarintExtendedHeaderRequest(dAtA, i, uint64(m.StatusCode))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *ExtendedHeaderRequest_Origin) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *CompactHeaders) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	n += 1 + sovExtendedHeaderRequest(uint64(m.Origin))
-	return n
+	return dAtA[:n], nil
 }
-func (m *ExtendedHeaderRequest_Hash) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *CompactHeaders) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CompactHeaders) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Hash != nil {
-		l = len(m.Hash)
-		n += 1 + l + sovExtendedHeaderRequest(uint64(l))
+	if len(m.Headers) > 0 {
+		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Headers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintExtendedHeaderRequest(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
-func (m *ExtendedHeaderResponse) Size() (n int) {
-	if m == nil {
-		return 0
+
+func (m *CompactHeader) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *CompactHeader) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CompactHeader) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Body)
-	if l > 0 {
-		n += 1 + l + sovExtendedHeaderRequest(uint64(l))
+	if len(m.ValidatorSetHash) > 0 {
+		i -= len(m.ValidatorSetHash)
+		copy(dAtA[i:], m.ValidatorSetHash)
+		i = encodeVarintExtendedHeaderRequest(dAtA, i, uint64(len(m.ValidatorSetHash)))
+		i--
+		dAtA[i] = 0x12
 	}
-	if m.StatusCode != 0 {
-		n += 1 + sovExtendedHeaderRequest(uint64(m.StatusCode))
+	if len(m.Header) > 0 {
+		i -= len(m.Header)
+		copy(dAtA[i:], m.Header)
+		i = encodeVarintExtendedHeaderRequest(dAtA, i, uint64(len(m.Header)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func sovExtendedHeaderRequest(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
+func (m *NetworkParamsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NetworkParamsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NetworkParamsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.BlockTime != 0 {
+		i = encodeVarintExtendedHeaderRequest(dAtA, i, uint64(m.BlockTime))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.TrustingPeriod != 0 {
+		i = encodeVarintExtendedHeaderRequest(dAtA, i, uint64(m.TrustingPeriod))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.StatusCode != 0 {
+		i = encodeVarintExtendedHeaderRequest(dAtA, i, uint64(m.StatusCode))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintExtendedHeaderRequest(dAtA []byte, offset int, v uint64) int {
+	offset -= sovExtendedHeaderRequest(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *ExtendedHeaderRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Data != nil {
+		n += m.Data.Size()
+	}
+	if m.Amount != 0 {
+		n += 1 + sovExtendedHeaderRequest(uint64(m.Amount))
+	}
+	if m.Compression != 0 {
+		n += 1 + sovExtendedHeaderRequest(uint64(m.Compression))
+	}
+	return n
+}
+
+func (m *ExtendedHeaderRequest_Origin) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovExtendedHeaderRequest(uint64(m.Origin))
+	return n
+}
+func (m *ExtendedHeaderRequest_Hash) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Hash != nil {
+		l = len(m.Hash)
+		n += 1 + l + sovExtendedHeaderRequest(uint64(l))
+	}
+	return n
+}
+func (m *ExtendedHeaderResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Body)
+	if l > 0 {
+		n += 1 + l + sovExtendedHeaderRequest(uint64(l))
+	}
+	if m.StatusCode != 0 {
+		n += 1 + sovExtendedHeaderRequest(uint64(m.StatusCode))
+	}
+	return n
+}
+
+func (m *ExtendedHeaderBatch) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.StatusCode != 0 {
+		n += 1 + sovExtendedHeaderRequest(uint64(m.StatusCode))
+	}
+	if m.Compression != 0 {
+		n += 1 + sovExtendedHeaderRequest(uint64(m.Compression))
+	}
+	l = len(m.Body)
+	if l > 0 {
+		n += 1 + l + sovExtendedHeaderRequest(uint64(l))
+	}
+	return n
+}
+
+func (m *CompactHeaders) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Headers) > 0 {
+		for _, e := range m.Headers {
+			l = e.Size()
+			n += 1 + l + sovExtendedHeaderRequest(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *CompactHeader) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Header)
+	if l > 0 {
+		n += 1 + l + sovExtendedHeaderRequest(uint64(l))
+	}
+	l = len(m.ValidatorSetHash)
+	if l > 0 {
+		n += 1 + l + sovExtendedHeaderRequest(uint64(l))
+	}
+	return n
+}
+
+func (m *NetworkParamsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.StatusCode != 0 {
+		n += 1 + sovExtendedHeaderRequest(uint64(m.StatusCode))
+	}
+	if m.TrustingPeriod != 0 {
+		n += 1 + sovExtendedHeaderRequest(uint64(m.TrustingPeriod))
+	}
+	if m.BlockTime != 0 {
+		n += 1 + sovExtendedHeaderRequest(uint64(m.BlockTime))
+	}
+	return n
+}
+
+func sovExtendedHeaderRequest(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
 }
 func sozExtendedHeaderRequest(x uint64) (n int) {
 	return sovExtendedHeaderRequest(uint64((x << 1) ^ uint64((int64(x) >> 63))))
@@ -501,6 +1013,25 @@ func (m *ExtendedHeaderRequest) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compression", wireType)
+			}
+			m.Compression = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExtendedHeaderRequest
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Compression |= Compression(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipExtendedHeaderRequest(dAtA[iNdEx:])
@@ -625,6 +1156,437 @@ func (m *ExtendedHeaderResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ExtendedHeaderBatch) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowExtendedHeaderRequest
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExtendedHeaderBatch: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExtendedHeaderBatch: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StatusCode", wireType)
+			}
+			m.StatusCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExtendedHeaderRequest
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StatusCode |= StatusCode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compression", wireType)
+			}
+			m.Compression = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExtendedHeaderRequest
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Compression |= Compression(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Body", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExtendedHeaderRequest
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthExtendedHeaderRequest
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExtendedHeaderRequest
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Body = append(m.Body[:0], dAtA[iNdEx:postIndex]...)
+			if m.Body == nil {
+				m.Body = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipExtendedHeaderRequest(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthExtendedHeaderRequest
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CompactHeaders) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowExtendedHeaderRequest
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CompactHeaders: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CompactHeaders: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExtendedHeaderRequest
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthExtendedHeaderRequest
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthExtendedHeaderRequest
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Headers = append(m.Headers, &CompactHeader{})
+			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipExtendedHeaderRequest(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthExtendedHeaderRequest
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CompactHeader) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowExtendedHeaderRequest
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CompactHeader: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CompactHeader: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExtendedHeaderRequest
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthExtendedHeaderRequest
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExtendedHeaderRequest
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Header = append(m.Header[:0], dAtA[iNdEx:postIndex]...)
+			if m.Header == nil {
+				m.Header = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValidatorSetHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExtendedHeaderRequest
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthExtendedHeaderRequest
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExtendedHeaderRequest
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ValidatorSetHash = append(m.ValidatorSetHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.ValidatorSetHash == nil {
+				m.ValidatorSetHash = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipExtendedHeaderRequest(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthExtendedHeaderRequest
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NetworkParamsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowExtendedHeaderRequest
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NetworkParamsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NetworkParamsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StatusCode", wireType)
+			}
+			m.StatusCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExtendedHeaderRequest
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StatusCode |= StatusCode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TrustingPeriod", wireType)
+			}
+			m.TrustingPeriod = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExtendedHeaderRequest
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TrustingPeriod |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockTime", wireType)
+			}
+			m.BlockTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExtendedHeaderRequest
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BlockTime |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipExtendedHeaderRequest(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthExtendedHeaderRequest
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipExtendedHeaderRequest(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
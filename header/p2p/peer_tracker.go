@@ -0,0 +1,238 @@
+package p2p
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/event"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// latencySmoothing is the weight of the latest measurement in the peer's average latency.
+const latencySmoothing = 0.3
+
+var (
+	// disconnectedPeerTTL is how long the statistics of disconnected peers are kept,
+	// so peers can not reset their score by reconnecting.
+	disconnectedPeerTTL = time.Hour
+	// peerTrackerGCInterval is the interval the statistics of disconnected peers are forgotten at.
+	peerTrackerGCInterval = time.Minute
+)
+
+// peerStat keeps the request statistics of a single peer.
+type peerStat struct {
+	// latency is an exponentially weighted moving average of the response latency.
+	latency time.Duration
+	// successes is the amount of requests served successfully.
+	successes uint64
+	// failures is the amount of requests failed due to networking or remote errors.
+	failures uint64
+	// invalid is the amount of responses not matching the request.
+	invalid uint64
+	// disconnectedAt is the time the peer disconnected at, if it is not connected.
+	disconnectedAt time.Time
+}
+
+// score rates the peer from 0 to 1, where higher is better.
+// It rewards a high success rate and a low latency, while invalid responses are punished harder than failures.
+// Peers without any statistics get an average score, so they have a chance to be tried.
+func (ps *peerStat) score() float64 {
+	// Laplace smoothing keeps the rate meaningful for peers with few requests
+	rate := float64(ps.successes+1) / float64(ps.successes+ps.failures+ps.invalid+2)
+	rate /= float64(1 + 2*ps.invalid)
+	return rate / (1 + ps.latency.Seconds())
+}
+
+// peerTracker discovers connected peers supporting the header exchange protocol
+// and keeps track of their responsiveness, also for some time after they disconnect.
+type peerTracker struct {
+	host host.Host
+
+	peerLk sync.RWMutex
+	peers  map[peer.ID]*peerStat
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newPeerTracker(h host.Host) *peerTracker {
+	return &peerTracker{
+		host:  h,
+		peers: make(map[peer.ID]*peerStat),
+	}
+}
+
+// start begins tracking of already connected peers and peers connecting later on.
+func (pt *peerTracker) start() error {
+	sub, err := pt.host.EventBus().Subscribe([]interface{}{
+		&event.EvtPeerIdentificationCompleted{},
+		&event.EvtPeerProtocolsUpdated{},
+		&event.EvtPeerConnectednessChanged{},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pt.host.Network().Peers() {
+		pt.connected(p)
+	}
+
+	var ctx context.Context
+	ctx, pt.cancel = context.WithCancel(context.Background())
+	pt.done = make(chan struct{})
+	go pt.track(ctx, sub)
+	return nil
+}
+
+// stop terminates peer tracking.
+func (pt *peerTracker) stop(ctx context.Context) error {
+	if pt.cancel == nil {
+		return nil
+	}
+	pt.cancel()
+
+	select {
+	case <-pt.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (pt *peerTracker) track(ctx context.Context, sub event.Subscription) {
+	defer close(pt.done)
+	defer func() {
+		if err := sub.Close(); err != nil {
+			log.Errorw("closing peer tracker subscription", "err", err)
+		}
+	}()
+	ticker := time.NewTicker(peerTrackerGCInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			pt.gc(now)
+		case e, ok := <-sub.Out():
+			if !ok {
+				return
+			}
+
+			switch e := e.(type) {
+			case event.EvtPeerIdentificationCompleted:
+				pt.connected(e.Peer)
+			case event.EvtPeerProtocolsUpdated:
+				pt.connected(e.Peer)
+			case event.EvtPeerConnectednessChanged:
+				if e.Connectedness == network.NotConnected {
+					pt.disconnected(e.Peer)
+				}
+			}
+		}
+	}
+}
+
+// connected starts tracking the peer if it supports the header exchange protocol.
+func (pt *peerTracker) connected(p peer.ID) {
+	if p == pt.host.ID() {
+		return
+	}
+
+	protos, err := pt.host.Peerstore().SupportsProtocols(p, string(exchangeProtocolIDv2), string(exchangeProtocolID))
+	if err != nil || len(protos) == 0 {
+		return
+	}
+
+	pt.peerLk.Lock()
+	defer pt.peerLk.Unlock()
+	stat, ok := pt.peers[p]
+	if !ok {
+		log.Debugw("tracking peer", "peer", p)
+		pt.peers[p] = &peerStat{}
+		return
+	}
+	// the reconnected peer keeps its score
+	stat.disconnectedAt = time.Time{}
+}
+
+// disconnected stops requesting the peer, keeping its statistics until they are garbage collected.
+func (pt *peerTracker) disconnected(p peer.ID) {
+	pt.peerLk.Lock()
+	defer pt.peerLk.Unlock()
+	if stat, ok := pt.peers[p]; ok && stat.disconnectedAt.IsZero() {
+		stat.disconnectedAt = time.Now()
+	}
+}
+
+// gc forgets the statistics of the peers disconnected for longer than disconnectedPeerTTL.
+func (pt *peerTracker) gc(now time.Time) {
+	pt.peerLk.Lock()
+	defer pt.peerLk.Unlock()
+	for p, stat := range pt.peers {
+		if !stat.disconnectedAt.IsZero() && now.Sub(stat.disconnectedAt) > disconnectedPeerTTL {
+			delete(pt.peers, p)
+		}
+	}
+}
+
+// bestPeers returns the connected tracked peers sorted by their score in decreasing order.
+func (pt *peerTracker) bestPeers() peer.IDSlice {
+	pt.peerLk.RLock()
+	defer pt.peerLk.RUnlock()
+
+	peers := make(peer.IDSlice, 0, len(pt.peers))
+	scores := make(map[peer.ID]float64, len(pt.peers))
+	for p, stat := range pt.peers {
+		if !stat.disconnectedAt.IsZero() {
+			continue
+		}
+		peers = append(peers, p)
+		scores[p] = stat.score()
+	}
+
+	sort.SliceStable(peers, func(i, j int) bool {
+		return scores[peers[i]] > scores[peers[j]]
+	})
+	return peers
+}
+
+// success records the successful response from the peer received within the given latency.
+func (pt *peerTracker) success(p peer.ID, latency time.Duration) {
+	pt.update(p, func(stat *peerStat) {
+		stat.successes++
+		if stat.latency == 0 {
+			stat.latency = latency
+			return
+		}
+		stat.latency = time.Duration(latencySmoothing*float64(latency) + (1-latencySmoothing)*float64(stat.latency))
+	})
+}
+
+// failure records the failed request to the peer.
+func (pt *peerTracker) failure(p peer.ID) {
+	pt.update(p, func(stat *peerStat) {
+		stat.failures++
+	})
+}
+
+// invalid records the invalid response from the peer.
+func (pt *peerTracker) invalid(p peer.ID) {
+	pt.update(p, func(stat *peerStat) {
+		stat.invalid++
+	})
+}
+
+func (pt *peerTracker) update(p peer.ID, f func(*peerStat)) {
+	pt.peerLk.Lock()
+	defer pt.peerLk.Unlock()
+	// untracked peers, e.g. trusted peers not connected yet, are not recorded
+	if stat, ok := pt.peers[p]; ok {
+		f(stat)
+	}
+}
@@ -0,0 +1,165 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerTracker_Scoring(t *testing.T) {
+	net, err := mocknet.FullMeshConnected(1)
+	require.NoError(t, err)
+	pt := newPeerTracker(net.Hosts()[0])
+
+	fast, slow, faulty, malicious, fresh := peer.ID("fast"), peer.ID("slow"), peer.ID("faulty"),
+		peer.ID("malicious"), peer.ID("fresh")
+	for _, p := range []peer.ID{fast, slow, faulty, malicious, fresh} {
+		pt.peers[p] = &peerStat{}
+	}
+
+	for i := 0; i < 10; i++ {
+		pt.success(fast, time.Millisecond*50)
+		pt.success(slow, time.Second*2)
+		pt.success(faulty, time.Millisecond*50)
+		pt.failure(faulty)
+		pt.success(malicious, time.Millisecond*50)
+	}
+	pt.invalid(malicious)
+	// untracked peers are ignored
+	pt.success("unknown", time.Millisecond)
+
+	assert.Equal(t, peer.IDSlice{fast, fresh, faulty, slow, malicious}, pt.bestPeers())
+}
+
+// TestPeerTracker_Discovery tests that the Exchange discovers connected peers serving headers
+// and requests headers from them in addition to the trusted peers.
+func TestPeerTracker_Discovery(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	t.Cleanup(cancel)
+
+	net := mocknet.New()
+	host, err := net.GenPeer()
+	require.NoError(t, err)
+	server, err := net.GenPeer()
+	require.NoError(t, err)
+	// the peer does not serve headers
+	_, err = net.GenPeer()
+	require.NoError(t, err)
+
+	store := createStore(t, 5)
+	serv := NewExchangeServer(server, store)
+	err = serv.Start(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		serv.Stop(context.Background()) //nolint:errcheck
+	})
+
+	// no trusted peers at all
+	exchg := NewExchange(host, nil)
+	err = exchg.Start(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		exchg.Stop(context.Background()) //nolint:errcheck
+	})
+
+	require.NoError(t, net.LinkAll())
+	require.NoError(t, net.ConnectAllButSelf())
+	require.Eventually(t, func() bool {
+		return len(exchg.peerTracker.bestPeers()) == 1
+	}, time.Second*5, time.Millisecond*50)
+	assert.Equal(t, server.ID(), exchg.peerTracker.bestPeers()[0])
+
+	got, err := exchg.GetByHeight(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, store.headers[3].Hash(), got.Hash())
+	assert.EqualValues(t, 1, exchg.peerTracker.peers[server.ID()].successes)
+
+	err = net.DisconnectPeers(host.ID(), server.ID())
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return len(exchg.peerTracker.bestPeers()) == 0
+	}, time.Second*5, time.Millisecond*50)
+
+	// the peer keeps its statistics once reconnected
+	_, err = net.ConnectPeers(host.ID(), server.ID())
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return len(exchg.peerTracker.bestPeers()) == 1
+	}, time.Second*5, time.Millisecond*50)
+	exchg.peerTracker.peerLk.RLock()
+	defer exchg.peerTracker.peerLk.RUnlock()
+	assert.EqualValues(t, 1, exchg.peerTracker.peers[server.ID()].successes)
+}
+
+func TestPeerTracker_GC(t *testing.T) {
+	net, err := mocknet.FullMeshConnected(1)
+	require.NoError(t, err)
+	pt := newPeerTracker(net.Hosts()[0])
+
+	connected, recent, stale := peer.ID("connected"), peer.ID("recent"), peer.ID("stale")
+	for _, p := range []peer.ID{connected, recent, stale} {
+		pt.peers[p] = &peerStat{}
+		pt.invalid(p)
+	}
+	now := time.Now()
+	pt.peers[recent].disconnectedAt = now.Add(-disconnectedPeerTTL / 2)
+	pt.peers[stale].disconnectedAt = now.Add(-disconnectedPeerTTL * 2)
+	// disconnected peers are not requested
+	assert.Equal(t, peer.IDSlice{connected}, pt.bestPeers())
+
+	pt.gc(now)
+	assert.Contains(t, pt.peers, connected)
+	assert.Contains(t, pt.peers, recent)
+	assert.NotContains(t, pt.peers, stale)
+	// the bad score is kept while the peer is remembered
+	assert.EqualValues(t, 1, pt.peers[recent].invalid)
+}
+
+// TestExchange_ReportInvalid tests that the peers serving headers failing verification are punished.
+func TestExchange_ReportInvalid(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	t.Cleanup(cancel)
+
+	net := mocknet.New()
+	host, err := net.GenPeer()
+	require.NoError(t, err)
+	server, err := net.GenPeer()
+	require.NoError(t, err)
+
+	store := createStore(t, 5)
+	serv := NewExchangeServer(server, store)
+	err = serv.Start(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		serv.Stop(context.Background()) //nolint:errcheck
+	})
+
+	exchg := NewExchange(host, nil)
+	err = exchg.Start(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		exchg.Stop(context.Background()) //nolint:errcheck
+	})
+
+	require.NoError(t, net.LinkAll())
+	require.NoError(t, net.ConnectAllButSelf())
+	require.Eventually(t, func() bool {
+		return len(exchg.peerTracker.bestPeers()) == 1
+	}, time.Second*5, time.Millisecond*50)
+
+	got, err := exchg.GetRangeByHeight(ctx, 1, 3)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+
+	exchg.ReportInvalid(got[1])
+	assert.EqualValues(t, 1, exchg.peerTracker.peers[server.ID()].invalid)
+	// the same header is not punished twice, while the unknown ones are ignored
+	exchg.ReportInvalid(got[1])
+	exchg.ReportInvalid(store.headers[5])
+	assert.EqualValues(t, 1, exchg.peerTracker.peers[server.ID()].invalid)
+}
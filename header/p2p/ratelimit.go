@@ -0,0 +1,198 @@
+package p2p
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// Reasons for rate limiting a request.
+const (
+	limitedByInFlight = "in_flight"
+	limitedByRequests = "requests"
+	limitedByHeaders  = "headers"
+)
+
+// RateLimits defines the limits the ExchangeServer serves inbound requests within.
+// Zero value of any limit disables it.
+type RateLimits struct {
+	// RequestsPerSecond is the rate of requests a single peer can send.
+	RequestsPerSecond float64
+	// RequestsBurst is the amount of requests a single peer can send at once.
+	RequestsBurst int
+	// HeadersPerSecond is the rate of headers a single peer can request.
+	HeadersPerSecond float64
+	// HeadersBurst is the amount of headers a single peer can request at once.
+	HeadersBurst int
+	// MaxInFlight is the maximum amount of requests handled concurrently across all the peers.
+	MaxInFlight int
+}
+
+// DefaultRateLimits returns the limits fair enough for syncing peers,
+// but preventing a single peer from starving the server.
+// The limits are not applied unless configured, so the servers can opt in to them.
+func DefaultRateLimits() RateLimits {
+	return RateLimits{
+		RequestsPerSecond: 20,
+		RequestsBurst:     100,
+		HeadersPerSecond:  float64(maxRequestSize * 4),
+		HeadersBurst:      int(maxRequestSize * 8),
+		MaxInFlight:       128,
+	}
+}
+
+// Validate performs basic validation of the limits.
+func (rl RateLimits) Validate() error {
+	if rl.RequestsPerSecond < 0 || rl.HeadersPerSecond < 0 {
+		return fmt.Errorf("header/p2p: rate limits must not be negative")
+	}
+	if rl.RequestsBurst < 0 || rl.HeadersBurst < 0 || rl.MaxInFlight < 0 {
+		return fmt.Errorf("header/p2p: burst and in flight limits must not be negative")
+	}
+	if rl.RequestsPerSecond > 0 && rl.RequestsBurst == 0 {
+		return fmt.Errorf("header/p2p: requests burst must be positive to serve any request")
+	}
+	// otherwise, requests for the maximum amount of headers would never be served
+	if rl.HeadersPerSecond > 0 && uint64(rl.HeadersBurst) < maxRequestSize {
+		return fmt.Errorf("header/p2p: headers burst must be at least %d", maxRequestSize)
+	}
+	return nil
+}
+
+// tokenBucket allows events up to the burst at once, refilling at the given rate.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now,
+	}
+}
+
+// refill adds the tokens accrued since the last refill.
+func (tb *tokenBucket) refill(now time.Time) {
+	tb.tokens += now.Sub(tb.last).Seconds() * tb.rate
+	if tb.tokens > tb.burst {
+		tb.tokens = tb.burst
+	}
+	tb.last = now
+}
+
+// full reports whether the bucket has the burst of tokens available.
+func (tb *tokenBucket) full(now time.Time) bool {
+	tb.refill(now)
+	return tb.tokens == tb.burst
+}
+
+// peerLimits keeps the token buckets of a single peer. Nil bucket means the limit is disabled.
+type peerLimits struct {
+	requests *tokenBucket
+	headers  *tokenBucket
+}
+
+// rateLimiter limits inbound requests per peer and the amount of requests handled concurrently.
+type rateLimiter struct {
+	limits RateLimits
+	// inFlight holds a token for every request being handled, if limited
+	inFlight chan struct{}
+
+	peersLk sync.Mutex
+	peers   map[peer.ID]*peerLimits
+}
+
+func newRateLimiter(limits RateLimits) *rateLimiter {
+	rl := &rateLimiter{
+		limits: limits,
+		peers:  make(map[peer.ID]*peerLimits),
+	}
+	if limits.MaxInFlight > 0 {
+		rl.inFlight = make(chan struct{}, limits.MaxInFlight)
+	}
+	return rl
+}
+
+// acquire admits the request for the given amount of headers from the peer.
+// It returns the reason if the request is rate limited.
+// Otherwise, the request must be released once handled.
+func (rl *rateLimiter) acquire(p peer.ID, amount uint64) string {
+	if rl.inFlight != nil {
+		select {
+		case rl.inFlight <- struct{}{}:
+		default:
+			return limitedByInFlight
+		}
+	}
+
+	if reason := rl.take(p, amount, time.Now()); reason != "" {
+		rl.release()
+		return reason
+	}
+	return ""
+}
+
+// release frees the slot of the handled request.
+func (rl *rateLimiter) release() {
+	if rl.inFlight != nil {
+		<-rl.inFlight
+	}
+}
+
+// take takes the tokens for the request from the peer's buckets, if there are enough of them in all the buckets.
+func (rl *rateLimiter) take(p peer.ID, amount uint64, now time.Time) string {
+	rl.peersLk.Lock()
+	defer rl.peersLk.Unlock()
+
+	pl, ok := rl.peers[p]
+	if !ok {
+		pl = &peerLimits{}
+		if rl.limits.RequestsPerSecond > 0 {
+			pl.requests = newTokenBucket(rl.limits.RequestsPerSecond, rl.limits.RequestsBurst, now)
+		}
+		if rl.limits.HeadersPerSecond > 0 {
+			pl.headers = newTokenBucket(rl.limits.HeadersPerSecond, rl.limits.HeadersBurst, now)
+		}
+		rl.peers[p] = pl
+	}
+
+	if pl.requests != nil {
+		pl.requests.refill(now)
+		if pl.requests.tokens < 1 {
+			return limitedByRequests
+		}
+	}
+	if pl.headers != nil {
+		pl.headers.refill(now)
+		if pl.headers.tokens < float64(amount) {
+			return limitedByHeaders
+		}
+	}
+
+	if pl.requests != nil {
+		pl.requests.tokens--
+	}
+	if pl.headers != nil {
+		pl.headers.tokens -= float64(amount)
+	}
+	return ""
+}
+
+// gc forgets the peers that have their buckets full again, as they are equal to the new ones.
+func (rl *rateLimiter) gc(now time.Time) {
+	rl.peersLk.Lock()
+	defer rl.peersLk.Unlock()
+
+	for p, pl := range rl.peers {
+		if (pl.requests == nil || pl.requests.full(now)) && (pl.headers == nil || pl.headers.full(now)) {
+			delete(rl.peers, p)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+func TestRateLimiter_PerPeer(t *testing.T) {
+	rl := newRateLimiter(RateLimits{
+		RequestsPerSecond: 1,
+		RequestsBurst:     2,
+		HeadersPerSecond:  10,
+		HeadersBurst:      20,
+	})
+	now := time.Now()
+	a, b := peer.ID("a"), peer.ID("b")
+
+	assert.Empty(t, rl.take(a, 15, now))
+	// not enough headers left
+	assert.Equal(t, limitedByHeaders, rl.take(a, 10, now))
+	assert.Empty(t, rl.take(a, 5, now))
+	// the burst of requests is exhausted
+	assert.Equal(t, limitedByRequests, rl.take(a, 1, now))
+	// other peers are not affected
+	assert.Empty(t, rl.take(b, 20, now))
+
+	// the buckets refill over time
+	now = now.Add(time.Second)
+	assert.Equal(t, limitedByHeaders, rl.take(a, 11, now))
+	assert.Empty(t, rl.take(a, 10, now))
+
+	// only peers with full buckets are forgotten
+	rl.gc(now.Add(time.Second))
+	assert.Contains(t, rl.peers, a)
+	assert.NotContains(t, rl.peers, b)
+	rl.gc(now.Add(time.Second * 2))
+	assert.Empty(t, rl.peers)
+}
+
+func TestRateLimiter_InFlight(t *testing.T) {
+	rl := newRateLimiter(RateLimits{MaxInFlight: 2})
+
+	assert.Empty(t, rl.acquire("a", 1))
+	assert.Empty(t, rl.acquire("b", 1))
+	assert.Equal(t, limitedByInFlight, rl.acquire("c", 1))
+	rl.release()
+	assert.Empty(t, rl.acquire("c", 1))
+}
+
+func TestRateLimits_Validate(t *testing.T) {
+	assert.NoError(t, DefaultRateLimits().Validate())
+	assert.NoError(t, RateLimits{}.Validate())
+	assert.Error(t, RateLimits{RequestsPerSecond: -1}.Validate())
+	assert.Error(t, RateLimits{RequestsPerSecond: 1}.Validate())
+	assert.Error(t, RateLimits{HeadersPerSecond: 1, HeadersBurst: int(maxRequestSize) - 1}.Validate())
+}
+
+// TestExchange_RateLimited tests that requests over the server's limits
+// are rejected with the RATE_LIMITED status.
+func TestExchange_RateLimited(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	t.Cleanup(cancel)
+
+	net, err := mocknet.FullMeshConnected(2)
+	require.NoError(t, err)
+	host, tpeer := net.Hosts()[0], net.Hosts()[1]
+
+	store := createStore(t, 5)
+	params := &paramsGetter{params: &header.NetworkParams{TrustingPeriod: time.Hour, BlockTime: time.Second}}
+	serv := NewExchangeServer(tpeer, store, WithParamsGetter(params), WithRateLimits(RateLimits{
+		RequestsPerSecond: 0.001,
+		RequestsBurst:     2,
+	}))
+	err = serv.Start(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		serv.Stop(context.Background()) //nolint:errcheck
+	})
+	require.NoError(t, serv.InitMetrics())
+
+	exchg := NewExchange(host, peer.IDSlice{tpeer.ID()})
+	_, err = exchg.GetByHeight(ctx, 1)
+	require.NoError(t, err)
+	_, err = exchg.GetRangeByHeight(ctx, 1, 3)
+	require.NoError(t, err)
+	_, err = exchg.GetByHeight(ctx, 1)
+	assert.ErrorIs(t, err, header.ErrRateLimited)
+	// the network params are served under the same limits
+	_, err = exchg.requestParams(ctx, tpeer.ID())
+	assert.ErrorIs(t, err, header.ErrRateLimited)
+}
@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/libp2p/go-libp2p-core/host"
@@ -19,18 +20,43 @@ import (
 type ExchangeServer struct {
 	host  host.Host
 	store header.Store
+	// params serves the network parameters, if set
+	params header.ParamsGetter
+
+	limits  RateLimits
+	limiter *rateLimiter
+	metrics *serverMetrics
 
 	ctx    context.Context
 	cancel context.CancelFunc
 }
 
+// rateLimiterGCInterval is the interval rate limits of idle peers are forgotten at.
+var rateLimiterGCInterval = time.Minute
+
+// ServerOption configures optional ExchangeServer behaviour.
+type ServerOption func(*ExchangeServer)
+
+// WithRateLimits limits inbound requests per peer and the amount of requests handled concurrently.
+// Requests over the limits are responded with the RATE_LIMITED status.
+func WithRateLimits(limits RateLimits) ServerOption {
+	return func(serv *ExchangeServer) {
+		serv.limits = limits
+	}
+}
+
 // NewExchangeServer returns a new P2P server that handles inbound
 // header-related requests.
-func NewExchangeServer(host host.Host, store header.Store) *ExchangeServer {
-	return &ExchangeServer{
+func NewExchangeServer(host host.Host, store header.Store, opts ...ServerOption) *ExchangeServer {
+	serv := &ExchangeServer{
 		host:  host,
 		store: store,
 	}
+	for _, opt := range opts {
+		opt(serv)
+	}
+	serv.limiter = newRateLimiter(serv.limits)
+	return serv
 }
 
 // Start sets the stream handler for inbound header-related requests.
@@ -38,8 +64,13 @@ func (serv *ExchangeServer) Start(context.Context) error {
 	serv.ctx, serv.cancel = context.WithCancel(context.Background())
 	log.Info("server: listening for inbound header requests")
 
+	serv.host.SetStreamHandler(exchangeProtocolIDv2, serv.requestHandler)
 	serv.host.SetStreamHandler(exchangeProtocolID, serv.requestHandler)
+	if serv.params != nil {
+		serv.host.SetStreamHandler(paramsProtocolID, serv.paramsHandler)
+	}
 
+	go serv.gcRateLimits(serv.ctx)
 	return nil
 }
 
@@ -47,11 +78,13 @@ func (serv *ExchangeServer) Start(context.Context) error {
 func (serv *ExchangeServer) Stop(context.Context) error {
 	log.Info("server: stopping server")
 	serv.cancel()
+	serv.host.RemoveStreamHandler(exchangeProtocolIDv2)
 	serv.host.RemoveStreamHandler(exchangeProtocolID)
+	serv.host.RemoveStreamHandler(paramsProtocolID)
 	return nil
 }
 
-// requestHandler handles inbound ExtendedHeaderRequests.
+// requestHandler handles inbound ExtendedHeaderRequests of both protocol versions.
 func (serv *ExchangeServer) requestHandler(stream network.Stream) {
 	err := stream.SetReadDeadline(time.Now().Add(readDeadline))
 	if err != nil {
@@ -69,6 +102,15 @@ func (serv *ExchangeServer) requestHandler(stream network.Stream) {
 		log.Error(err)
 	}
 
+	from := stream.Conn().RemotePeer()
+	if reason := serv.limiter.acquire(from, requestedAmount(pbreq)); reason != "" {
+		log.Debugw("server: rate limiting peer", "peer", from, "reason", reason)
+		serv.metrics.observeRateLimited(serv.ctx, reason)
+		serv.respond(stream, pbreq, nil, p2p_pb.StatusCode_RATE_LIMITED)
+		return
+	}
+	defer serv.limiter.release()
+
 	var headers []*header.ExtendedHeader
 	// retrieve and write ExtendedHeaders
 	switch pbreq.Data.(type) {
@@ -82,18 +124,78 @@ func (serv *ExchangeServer) requestHandler(stream network.Stream) {
 		return
 	}
 	var code p2p_pb.StatusCode
-	switch err {
-	case nil:
+	switch {
+	case err == nil:
 		code = p2p_pb.StatusCode_OK
-	case header.ErrNotFound:
+	// the pruned headers are not served anymore, which is not a failure of the peer
+	case errors.Is(err, header.ErrNotFound), errors.Is(err, header.ErrPruned):
 		code = p2p_pb.StatusCode_NOT_FOUND
-	case header.ErrHeadersLimitExceeded:
+	case errors.Is(err, header.ErrHeadersLimitExceeded):
 		code = p2p_pb.StatusCode_LIMIT_EXCEEDED
 	default:
 		stream.Reset() //nolint:errcheck
 		return
 	}
+	serv.respond(stream, pbreq, headers, code)
+}
+
+// respond writes the response in the format of the stream's protocol version and closes the stream.
+func (serv *ExchangeServer) respond(
+	stream network.Stream,
+	pbreq *p2p_pb.ExtendedHeaderRequest,
+	headers []*header.ExtendedHeader,
+	code p2p_pb.StatusCode,
+) {
+	var err error
+	if stream.Protocol() == exchangeProtocolIDv2 {
+		err = serv.writeBatches(stream, headers, code, pbreq.Compression)
+	} else {
+		err = serv.writeResponses(stream, headers, code)
+	}
+	if err != nil {
+		stream.Reset() //nolint:errcheck
+		return
+	}
+
+	err = stream.Close()
+	if err != nil {
+		log.Errorw("while closing inbound stream", "err", err)
+	}
+}
+
+// requestedAmount returns the amount of headers the request is accounted for by the rate limits.
+func requestedAmount(pbreq *p2p_pb.ExtendedHeaderRequest) uint64 {
+	if pbreq.GetOrigin() == 0 {
+		// head or hash request
+		return 1
+	}
+	if pbreq.Amount > maxRequestSize {
+		// the request is not served anyway
+		return maxRequestSize
+	}
+	return pbreq.Amount
+}
+
+// gcRateLimits periodically forgets the rate limits of idle peers.
+func (serv *ExchangeServer) gcRateLimits(ctx context.Context) {
+	ticker := time.NewTicker(rateLimiterGCInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			serv.limiter.gc(now)
+		}
+	}
+}
 
+// writeResponses writes headers to the stream one by one as ExtendedHeaderResponses.
+func (serv *ExchangeServer) writeResponses(
+	stream network.Stream,
+	headers []*header.ExtendedHeader,
+	code p2p_pb.StatusCode,
+) error {
 	// reallocate headers with 1 nil ExtendedHeader if code is not StatusCode_OK
 	if code != p2p_pb.StatusCode_OK {
 		headers = make([]*header.ExtendedHeader, 1)
@@ -107,25 +209,45 @@ func (serv *ExchangeServer) requestHandler(stream network.Stream) {
 		// if header is not nil, then marshal it to []byte.
 		// if header is nil, then error was received,so we will set empty []byte to proto.
 		if h != nil {
+			var err error
 			bin, err = h.MarshalBinary()
 			if err != nil {
 				log.Errorw("server: marshaling header to proto", "height", h.Height, "err", err)
-				stream.Reset() //nolint:errcheck
-				return
+				return err
 			}
 		}
-		_, err = serde.Write(stream, &p2p_pb.ExtendedHeaderResponse{Body: bin, StatusCode: code})
+		_, err := serde.Write(stream, &p2p_pb.ExtendedHeaderResponse{Body: bin, StatusCode: code})
 		if err != nil {
-			log.Errorw("server: writing header to stream", "height", h.Height, "err", err)
-			stream.Reset() //nolint:errcheck
-			return
+			log.Errorw("server: writing header to stream", "err", err)
+			return err
 		}
 	}
+	return nil
+}
 
-	err = stream.Close()
+// writeBatches writes headers to the stream as compact ExtendedHeaderBatches.
+func (serv *ExchangeServer) writeBatches(
+	stream network.Stream,
+	headers []*header.ExtendedHeader,
+	code p2p_pb.StatusCode,
+	compression p2p_pb.Compression,
+) error {
+	if code != p2p_pb.StatusCode_OK {
+		if err := stream.SetWriteDeadline(time.Now().Add(writeDeadline)); err != nil {
+			log.Debugf("error setting deadline: %s", err)
+		}
+		_, err := serde.Write(stream, &p2p_pb.ExtendedHeaderBatch{StatusCode: code})
+		if err != nil {
+			log.Errorw("server: writing batch to stream", "err", err)
+		}
+		return err
+	}
+
+	err := writeBatches(stream, headers, compression)
 	if err != nil {
-		log.Errorw("while closing inbound stream", "err", err)
+		log.Errorw("server: writing batches to stream", "err", err)
 	}
+	return err
 }
 
 // handleRequestByHash returns the ExtendedHeader at the given hash
@@ -0,0 +1,36 @@
+package header
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// NetworkParams are the parameters of the network headers are synced and verified with.
+type NetworkParams struct {
+	// TrustingPeriod is the period through which a header's validator set can be trusted.
+	// It is derived from the unbonding period of the network.
+	TrustingPeriod time.Duration `json:"trusting_period"`
+	// BlockTime is the expected time between two consecutive headers.
+	BlockTime time.Duration `json:"block_time"`
+}
+
+// Validate performs basic validation of the NetworkParams.
+func (p *NetworkParams) Validate() error {
+	if p.TrustingPeriod <= 0 {
+		return fmt.Errorf("header: invalid trusting period %v", p.TrustingPeriod)
+	}
+	if p.BlockTime <= 0 {
+		return fmt.Errorf("header: invalid block time %v", p.BlockTime)
+	}
+	if p.BlockTime >= p.TrustingPeriod {
+		return fmt.Errorf("header: block time %v exceeds trusting period %v", p.BlockTime, p.TrustingPeriod)
+	}
+	return nil
+}
+
+// ParamsGetter gets the current NetworkParams.
+type ParamsGetter interface {
+	// NetworkParams returns the current parameters of the network.
+	NetworkParams(context.Context) (*NetworkParams, error)
+}
@@ -0,0 +1,217 @@
+// Package rpc provides the header.Exchange requesting headers from the RPC of a remote trusted node,
+// for the environments where the node can't reach its peers over libp2p.
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	logging "github.com/ipfs/go-log/v2"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+var log = logging.Logger("header/rpc")
+
+// binaryContentType is the content type accepted to get headers in the binary format.
+const binaryContentType = "application/octet-stream"
+
+// maxRequestSize is the maximum amount of headers requested at once, as limited by the RPC.
+var maxRequestSize uint64 = 512
+
+// Exchange requests headers from the RPC endpoints of a remote node.
+// The headers are not trusted, so they must be verified thereafter, e.g. by the Syncer.
+type Exchange struct {
+	url    string
+	client *http.Client
+}
+
+// Option configures optional Exchange behaviour.
+type Option func(*Exchange)
+
+// WithHTTPClient sets the client requests are sent with, e.g. to configure TLS or timeouts.
+// Defaults to http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(ex *Exchange) {
+		ex.client = client
+	}
+}
+
+// NewExchange creates a new Exchange requesting headers from the RPC at the given URL,
+// e.g. 'https://node.example.com:26658'.
+func NewExchange(url string, opts ...Option) *Exchange {
+	ex := &Exchange{
+		url:    strings.TrimSuffix(url, "/"),
+		client: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(ex)
+	}
+	return ex
+}
+
+// Head requests the latest ExtendedHeader of the remote node.
+func (ex *Exchange) Head(ctx context.Context) (*header.ExtendedHeader, error) {
+	log.Debug("requesting head")
+	return ex.requestHeader(ctx, "/head")
+}
+
+// GetByHeight requests the ExtendedHeader at the given height.
+// The remote node holds the request until it has the header.
+func (ex *Exchange) GetByHeight(ctx context.Context, height uint64) (*header.ExtendedHeader, error) {
+	log.Debugw("requesting header", "height", height)
+	return ex.requestHeader(ctx, fmt.Sprintf("/header/%d", height))
+}
+
+// GetRangeByHeight requests the given amount of ExtendedHeaders starting from the given height.
+// Ranges bigger than the RPC serves at once are requested in chunks.
+// Less headers are returned if the remote node does not have all of them yet.
+func (ex *Exchange) GetRangeByHeight(ctx context.Context, from, amount uint64) ([]*header.ExtendedHeader, error) {
+	log.Debugw("requesting headers", "from", from, "to", from+amount)
+	headers := make([]*header.ExtendedHeader, 0, amount)
+	for amount > 0 {
+		size := amount
+		if size > maxRequestSize {
+			size = maxRequestSize
+		}
+
+		hs, err := ex.requestRange(ctx, from, size)
+		if err != nil {
+			if errors.Is(err, header.ErrNotFound) && len(headers) != 0 {
+				// the remote node does not have the rest yet
+				return headers, nil
+			}
+			return nil, err
+		}
+		headers = append(headers, hs...)
+		if uint64(len(hs)) < size {
+			return headers, nil
+		}
+		from, amount = from+size, amount-size
+	}
+	return headers, nil
+}
+
+// Get requests the ExtendedHeader with the given hash.
+func (ex *Exchange) Get(ctx context.Context, hash tmbytes.HexBytes) (*header.ExtendedHeader, error) {
+	log.Debugw("requesting header", "hash", hash.String())
+	h, err := ex.requestHeader(ctx, fmt.Sprintf("/header/hash/%s", hash.String()))
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(h.Hash(), hash) {
+		return nil, fmt.Errorf("incorrect hash in header: expected %x, got %x", hash, h.Hash().Bytes())
+	}
+	return h, nil
+}
+
+// NetworkParams requests the parameters of the network the remote node syncs headers with.
+// As with the peers, the trusting period received is clamped to the local header.TrustingPeriod.
+func (ex *Exchange) NetworkParams(ctx context.Context) (*header.NetworkParams, error) {
+	log.Debug("requesting network params")
+	body, err := ex.request(ctx, "/header/params")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	p := new(header.NetworkParams)
+	err = json.NewDecoder(body).Decode(p)
+	if err != nil {
+		return nil, err
+	}
+	if p.TrustingPeriod > header.TrustingPeriod {
+		log.Warnw("clamping trusting period received from remote node",
+			"received", p.TrustingPeriod, "max", header.TrustingPeriod)
+		p.TrustingPeriod = header.TrustingPeriod
+	}
+	return p, p.Validate()
+}
+
+// requestHeader requests a single header from the given endpoint.
+func (ex *Exchange) requestHeader(ctx context.Context, endpoint string) (*header.ExtendedHeader, error) {
+	body, err := ex.request(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	return header.UnmarshalExtendedHeader(data)
+}
+
+// requestRange requests the range of headers and checks the headers are the requested ones.
+func (ex *Exchange) requestRange(ctx context.Context, from, amount uint64) ([]*header.ExtendedHeader, error) {
+	body, err := ex.request(ctx, fmt.Sprintf("/header/range/%d/%d", from, amount))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	headers := make([]*header.ExtendedHeader, 0, amount)
+	r := bufio.NewReader(body)
+	for {
+		h, err := header.ReadDelimited(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(headers)) == amount {
+			return nil, fmt.Errorf("header/rpc: more headers than requested %d", amount)
+		}
+		if expected := from + uint64(len(headers)); uint64(h.Height) != expected {
+			return nil, fmt.Errorf("header/rpc: unexpected header height: expected %d, got %d", expected, h.Height)
+		}
+		headers = append(headers, h)
+	}
+	if len(headers) == 0 {
+		return nil, header.ErrNotFound
+	}
+	return headers, nil
+}
+
+// request performs the GET request to the given endpoint accepting the binary format
+// and returns the response body.
+func (ex *Exchange) request(ctx context.Context, endpoint string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ex.url+endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", binaryContentType)
+
+	resp, err := ex.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp.Body, nil
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return nil, header.ErrNotFound
+	case http.StatusGone:
+		return nil, header.ErrPruned
+	}
+	// errors are written as JSON strings
+	var msg string
+	data, err := io.ReadAll(resp.Body)
+	if err != nil || json.Unmarshal(data, &msg) != nil {
+		msg = string(data)
+	}
+	return nil, fmt.Errorf("header/rpc: %s responded with %s: %s", endpoint, resp.Status, msg)
+}
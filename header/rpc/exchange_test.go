@@ -0,0 +1,51 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+func TestExchange_NetworkParams(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	var params *header.NetworkParams
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/header/params" || params == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		err := json.NewEncoder(w).Encode(params)
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+	ex := NewExchange(server.URL)
+
+	// the remote node does not know the params yet
+	_, err := ex.NetworkParams(ctx)
+	require.ErrorIs(t, err, header.ErrNotFound)
+
+	params = &header.NetworkParams{TrustingPeriod: time.Hour, BlockTime: time.Second}
+	out, err := ex.NetworkParams(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, params, out)
+
+	// the trusting period is not extended beyond the local one
+	params = &header.NetworkParams{TrustingPeriod: header.TrustingPeriod * 10, BlockTime: time.Second}
+	out, err = ex.NetworkParams(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, header.TrustingPeriod, out.TrustingPeriod)
+
+	params = &header.NetworkParams{TrustingPeriod: time.Second, BlockTime: time.Hour}
+	_, err = ex.NetworkParams(ctx)
+	require.Error(t, err)
+}
@@ -1,6 +1,12 @@
 package header
 
 import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
 	core "github.com/tendermint/tendermint/types"
 
 	"github.com/celestiaorg/celestia-app/pkg/da"
@@ -8,6 +14,10 @@ import (
 	header_pb "github.com/celestiaorg/celestia-node/header/pb"
 )
 
+// maxDelimitedHeaderSize limits the size of a single header read by ReadDelimited,
+// so a malformed stream can't exhaust the memory.
+const maxDelimitedHeaderSize = 1 << 24
+
 // MarshalExtendedHeader serializes given ExtendedHeader to bytes using protobuf.
 // Paired with UnmarshalExtendedHeader.
 func MarshalExtendedHeader(in *ExtendedHeader) (_ []byte, err error) {
@@ -92,3 +102,48 @@ func ProtoToExtendedHeader(pb *header_pb.ExtendedHeader) (*ExtendedHeader, error
 	}
 	return header, nil
 }
+
+// WriteDelimited writes the header prefixed with its uvarint length,
+// so a stream of headers can be read back with ReadDelimited.
+func WriteDelimited(w io.Writer, h *ExtendedHeader) error {
+	b, err := MarshalExtendedHeader(h)
+	if err != nil {
+		return err
+	}
+
+	prefix := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(prefix, uint64(len(b)))
+	_, err = w.Write(prefix[:n])
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+// ReadDelimited reads the header prefixed with its uvarint length written by WriteDelimited.
+// It returns io.EOF once there are no more headers to read.
+func ReadDelimited(r *bufio.Reader) (*ExtendedHeader, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, fmt.Errorf("header: reading header size: %w", err)
+	}
+	if size > maxDelimitedHeaderSize {
+		return nil, fmt.Errorf("header: header size %d exceeds %d", size, maxDelimitedHeaderSize)
+	}
+
+	b := make([]byte, size)
+	_, err = io.ReadFull(r, b)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("header: reading header: %w", err)
+	}
+
+	return UnmarshalExtendedHeader(b)
+}
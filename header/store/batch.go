@@ -55,6 +55,18 @@ func (b *batch) Get(hash tmbytes.HexBytes) *header.ExtendedHeader {
 	return b.getByHeight(height)
 }
 
+// GetByDataHash returns the latest header with the given DataHash.
+func (b *batch) GetByDataHash(hash tmbytes.HexBytes) *header.ExtendedHeader {
+	b.lk.RLock()
+	defer b.lk.RUnlock()
+	for i := len(b.headers) - 1; i >= 0; i-- {
+		if b.headers[i].DataHash.String() == hash.String() {
+			return b.headers[i]
+		}
+	}
+	return nil
+}
+
 // GetByHeight returns a header by its height.
 func (b *batch) GetByHeight(height uint64) *header.ExtendedHeader {
 	b.lk.RLock()
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+	"strconv"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/ipfs/go-datastore"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+// dataIndexer stores and caches mappings between header DataHash and Height.
+// Headers of empty blocks share the same DataHash, so only the latest written
+// Height is kept for them.
+type dataIndexer struct {
+	ds    datastore.Batching
+	cache *lru.ARCCache
+}
+
+// newDataIndexer creates new dataIndexer.
+func newDataIndexer(ds datastore.Batching) (*dataIndexer, error) {
+	cache, err := lru.NewARC(DefaultIndexCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dataIndexer{
+		ds:    ds,
+		cache: cache,
+	}, nil
+}
+
+// HeightByDataHash loads a header height corresponding to the given DataHash.
+func (di *dataIndexer) HeightByDataHash(ctx context.Context, hash tmbytes.HexBytes) (uint64, error) {
+	if v, ok := di.cache.Get(hash.String()); ok {
+		return v.(uint64), nil
+	}
+
+	val, err := di.ds.Get(ctx, dataKey(hash))
+	if err != nil {
+		return 0, err
+	}
+
+	h, err := strconv.ParseUint(string(val), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	di.cache.Add(hash.String(), h)
+	return h, nil
+}
+
+// IndexTo saves mapping between header DataHash and Height to the given batch.
+func (di *dataIndexer) IndexTo(ctx context.Context, batch datastore.Batch, headers ...*header.ExtendedHeader) error {
+	for _, h := range headers {
+		err := batch.Put(ctx, dataKey(h.DataHash), []byte(strconv.FormatUint(uint64(h.Height), 10)))
+		if err != nil {
+			return err
+		}
+		di.cache.Remove(h.DataHash.String())
+	}
+
+	return nil
+}
+
+// IndexMissingTo saves mapping between header DataHash and Height to the given batch,
+// only for the DataHashes not indexed yet. It is used for the headers written below the indexed ones,
+// so that the latest Height is kept for the shared DataHashes.
+func (di *dataIndexer) IndexMissingTo(
+	ctx context.Context,
+	batch datastore.Batch,
+	headers ...*header.ExtendedHeader,
+) error {
+	// the headers may share the DataHash as well, so keep the latest of them
+	latest := make(map[string]*header.ExtendedHeader, len(headers))
+	for _, h := range headers {
+		if l, ok := latest[h.DataHash.String()]; !ok || h.Height > l.Height {
+			latest[h.DataHash.String()] = h
+		}
+	}
+
+	for _, h := range latest {
+		_, err := di.HeightByDataHash(ctx, h.DataHash)
+		switch err {
+		default:
+			return err
+		case nil:
+			continue
+		case datastore.ErrNotFound:
+		}
+
+		err = di.IndexTo(ctx, batch, h)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveTo removes mapping between header DataHash and Height to the given batch,
+// unless the DataHash is mapped to another header.
+func (di *dataIndexer) RemoveTo(ctx context.Context, batch datastore.Batch, h *header.ExtendedHeader) error {
+	height, err := di.HeightByDataHash(ctx, h.DataHash)
+	switch err {
+	default:
+		return err
+	case datastore.ErrNotFound:
+		return nil
+	case nil:
+	}
+	if height != uint64(h.Height) {
+		return nil
+	}
+
+	di.cache.Remove(h.DataHash.String())
+	return batch.Delete(ctx, dataKey(h.DataHash))
+}
@@ -56,3 +56,9 @@ func (hi *heightIndexer) IndexTo(ctx context.Context, batch datastore.Batch, hea
 
 	return nil
 }
+
+// RemoveTo removes mapping between header Height and Hash to the given batch.
+func (hi *heightIndexer) RemoveTo(ctx context.Context, batch datastore.Batch, h uint64) error {
+	hi.cache.Remove(h)
+	return batch.Delete(ctx, heightKey(h))
+}
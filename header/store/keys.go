@@ -4,19 +4,45 @@ import (
 	"strconv"
 
 	"github.com/ipfs/go-datastore"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
 
 	"github.com/celestiaorg/celestia-node/header"
 )
 
 var (
-	storePrefix = datastore.NewKey("headers")
-	headKey     = datastore.NewKey("head")
+	storePrefix  = datastore.NewKey("headers")
+	headKey      = datastore.NewKey("head")
+	tailKey      = datastore.NewKey("tail")
+	timePrefix   = datastore.NewKey("time")
+	dataPrefix   = datastore.NewKey("data")
+	valSetPrefix = datastore.NewKey("vals")
+	prunedPrefix = datastore.NewKey("pruned")
+	// indexedKey marks all the stored headers as indexed by data hash,
+	// which is not the case for the headers stored before the index was introduced
+	indexedKey = datastore.NewKey("indexed")
 )
 
 func heightKey(h uint64) datastore.Key {
 	return datastore.NewKey(strconv.Itoa(int(h)))
 }
 
+func timeKey(h uint64) datastore.Key {
+	return timePrefix.ChildString(strconv.Itoa(int(h)))
+}
+
+func dataKey(hash tmbytes.HexBytes) datastore.Key {
+	return dataPrefix.ChildString(hash.String())
+}
+
+func valSetKey(hash tmbytes.HexBytes) datastore.Key {
+	return valSetPrefix.ChildString(hash.String())
+}
+
+// prunedKey marks the header with the given hash as pruned.
+func prunedKey(hash tmbytes.HexBytes) datastore.Key {
+	return prunedPrefix.ChildString(hash.String())
+}
+
 func headerKey(h *header.ExtendedHeader) datastore.Key {
 	return datastore.NewKey(h.Hash().String())
 }
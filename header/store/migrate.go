@@ -0,0 +1,170 @@
+package store
+
+import (
+	"context"
+
+	"github.com/ipfs/go-datastore"
+
+	"github.com/celestiaorg/celestia-node/header"
+	header_pb "github.com/celestiaorg/celestia-node/header/pb"
+)
+
+// MigrateValidatorSets rewrites the headers stored in the datastore with their validator sets embedded,
+// storing the sets once instead. Headers are read the same way before and after the migration.
+func MigrateValidatorSets(ctx context.Context, ds datastore.Batching) error {
+	s, err := openForMigration(ctx, ds)
+	if err != nil {
+		return err
+	}
+
+	migrated, err := s.rewrite(ctx, func(
+		ctx context.Context,
+		batch datastore.Batch,
+		written map[string]struct{},
+		key datastore.Key,
+		b []byte,
+	) (bool, error) {
+		pb := &header_pb.ExtendedHeader{}
+		err := pb.Unmarshal(b)
+		if err != nil {
+			return false, err
+		}
+		if pb.ValidatorSet == nil || pb.Header == nil {
+			return false, nil
+		}
+
+		return true, s.putHeaderTo(ctx, batch, written, key, pb)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infow("deduplicated validator sets of stored headers", "headers", migrated)
+	return nil
+}
+
+// MigrateIndexes indexes the headers stored in the datastore before
+// they were indexed by time and data hash. Until then, lookups by data hash fail with ErrIndexNotBuilt.
+func MigrateIndexes(ctx context.Context, ds datastore.Batching) error {
+	s, err := openForMigration(ctx, ds)
+	if err != nil {
+		return err
+	}
+
+	migrated, err := s.rewrite(ctx, func(
+		ctx context.Context,
+		batch datastore.Batch,
+		_ map[string]struct{},
+		_ datastore.Key,
+		b []byte,
+	) (bool, error) {
+		h, err := s.unmarshalHeader(ctx, b)
+		if err != nil {
+			return false, err
+		}
+
+		err = s.timeIndex.IndexTo(ctx, batch, h)
+		if err != nil {
+			return false, err
+		}
+
+		return true, s.dataIndex.IndexTo(ctx, batch, h)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.ds.Put(ctx, indexedKey, nil)
+	if err != nil {
+		return err
+	}
+
+	log.Infow("indexed stored headers", "headers", migrated)
+	return nil
+}
+
+// openForMigration opens the store over the datastore without starting it.
+func openForMigration(ctx context.Context, ds datastore.Batching) (*store, error) {
+	s, err := newStore(ds, RetentionPolicy{})
+	if err != nil {
+		return nil, err
+	}
+
+	return s, s.loadTail(ctx)
+}
+
+// rewriteFn rewrites the stored header under the given key to the batch.
+// It reports whether the header was rewritten.
+type rewriteFn func(
+	ctx context.Context,
+	batch datastore.Batch,
+	written map[string]struct{},
+	key datastore.Key,
+	b []byte,
+) (bool, error)
+
+// rewrite rewrites all the stored headers from the tail to the head with the given function
+// in batches of DefaultWriteBatchSize. It returns the amount of rewritten headers.
+func (s *store) rewrite(ctx context.Context, fn rewriteFn) (int, error) {
+	var head uint64
+	h, err := s.readHead(ctx)
+	switch err {
+	default:
+		return 0, err
+	case datastore.ErrNotFound, header.ErrNotFound:
+		// the store is not initialized yet, so there is nothing to rewrite
+		return 0, nil
+	case nil:
+		head = uint64(h.Height)
+	}
+
+	batch, err := s.ds.Batch(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var rewritten int
+	// tracks validator sets written within the batch
+	written := make(map[string]struct{})
+	for height := s.tailHeight.Load(); height != 0 && height <= head; height++ {
+		hash, err := s.heightIndex.HashByHeight(ctx, height)
+		if err != nil {
+			if err == datastore.ErrNotFound {
+				continue
+			}
+			return rewritten, err
+		}
+
+		key := datastore.NewKey(hash.String())
+		b, err := s.ds.Get(ctx, key)
+		if err != nil {
+			return rewritten, err
+		}
+
+		ok, err := fn(ctx, batch, written, key, b)
+		if err != nil {
+			return rewritten, err
+		}
+		if !ok {
+			continue
+		}
+
+		rewritten++
+		if rewritten%DefaultWriteBatchSize != 0 {
+			continue
+		}
+
+		err = batch.Commit(ctx)
+		if err != nil {
+			return rewritten, err
+		}
+
+		batch, err = s.ds.Batch(ctx)
+		if err != nil {
+			return rewritten, err
+		}
+		written = make(map[string]struct{})
+	}
+
+	return rewritten, batch.Commit(ctx)
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/celestia-app/pkg/da"
+
+	"github.com/celestiaorg/celestia-node/header"
+	header_pb "github.com/celestiaorg/celestia-node/header/pb"
+	"github.com/celestiaorg/celestia-node/share"
+)
+
+// TestMigrateValidatorSets tests that headers stored with their validator sets embedded
+// are read the same way before and after their sets are deduplicated.
+func TestMigrateValidatorSets(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+	in := suite.GenExtendedHeaders(10)
+	ds := legacyStore(ctx, t, in)
+
+	test := func() {
+		store, err := NewStore(ds)
+		require.NoError(t, err)
+		_, err = store.Head(ctx)
+		require.NoError(t, err)
+
+		out, err := store.GetRangeByHeight(ctx, 1, 11)
+		require.NoError(t, err)
+		for i, h := range in {
+			assert.Equal(t, h.Hash(), out[i].Hash())
+			assert.Equal(t, h.ValidatorSet.Hash(), out[i].ValidatorSet.Hash())
+		}
+	}
+	test()
+
+	err := MigrateValidatorSets(ctx, ds)
+	require.NoError(t, err)
+	test()
+
+	nds := namespace.Wrap(ds, storePrefix)
+	for _, h := range in {
+		b, err := nds.Get(ctx, headerKey(h))
+		require.NoError(t, err)
+
+		pb := &header_pb.ExtendedHeader{}
+		require.NoError(t, pb.Unmarshal(b))
+		assert.Nil(t, pb.ValidatorSet)
+	}
+	ok, err := nds.Has(ctx, valSetKey(in[0].ValidatorsHash))
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// migrating twice is a no-op
+	err = MigrateValidatorSets(ctx, ds)
+	require.NoError(t, err)
+	test()
+}
+
+func TestMigrateIndexes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+	in := []*header.ExtendedHeader{suite.Head()}
+	for i := 0; i < 5; i++ {
+		dah := da.NewDataAvailabilityHeader(share.RandEDS(t, 2))
+		in = append(in, suite.GenExtendedHeaderWithDAH(&dah))
+	}
+	ds := legacyStore(ctx, t, in)
+
+	store, err := NewStore(ds)
+	require.NoError(t, err)
+	_, err = store.Head(ctx)
+	require.NoError(t, err)
+	_, err = store.GetByDataHash(ctx, in[3].DataHash)
+	assert.ErrorIs(t, err, ErrIndexNotBuilt)
+
+	err = MigrateIndexes(ctx, ds)
+	require.NoError(t, err)
+
+	store, err = NewStore(ds)
+	require.NoError(t, err)
+	err = store.Start(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := store.Stop(ctx)
+		require.NoError(t, err)
+	})
+	_, err = store.Head(ctx)
+	require.NoError(t, err)
+	for _, h := range in {
+		out, err := store.GetByDataHash(ctx, h.DataHash)
+		require.NoError(t, err)
+		assert.Equal(t, h.Hash(), out.Hash())
+
+		out, err = store.GetByTime(ctx, h.Time, header.TimeExact)
+		require.NoError(t, err)
+		assert.Equal(t, h.Hash(), out.Hash())
+	}
+	_, err = store.GetByDataHash(ctx, suite.GenExtendedHeaders(1)[0].DataHash)
+	assert.ErrorIs(t, err, header.ErrNotFound)
+}
+
+// legacyStore writes the given headers the way they were stored before validator sets
+// were deduplicated and headers were indexed by time and data hash.
+func legacyStore(ctx context.Context, t *testing.T, headers []*header.ExtendedHeader) datastore.Batching {
+	ds := sync.MutexWrap(datastore.NewMapDatastore())
+	nds := namespace.Wrap(ds, storePrefix)
+	for _, h := range headers {
+		b, err := h.MarshalBinary()
+		require.NoError(t, err)
+		require.NoError(t, nds.Put(ctx, headerKey(h), b))
+		require.NoError(t, nds.Put(ctx, heightKey(uint64(h.Height)), h.Hash()))
+	}
+
+	b, err := headers[len(headers)-1].Hash().MarshalJSON()
+	require.NoError(t, err)
+	require.NoError(t, nds.Put(ctx, headKey, b))
+	require.NoError(t, nds.Put(ctx, tailKey, []byte("1")))
+	return ds
+}
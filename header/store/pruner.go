@@ -0,0 +1,272 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+// RetentionPolicy defines which headers the Store keeps on disk.
+// Headers falling out of the retention window are pruned in the background.
+// If both fields are set, a header is pruned only when it is outside of both windows.
+// The zero value keeps all the headers.
+type RetentionPolicy struct {
+	// Heights is the amount of the most recent headers to keep.
+	Heights uint64
+	// Period is the time window relative to the head's timestamp to keep headers within.
+	Period time.Duration
+}
+
+// enabled reports whether the policy requires pruning.
+func (rp RetentionPolicy) enabled() bool {
+	return rp.Heights > 0 || rp.Period > 0
+}
+
+// pruneLoop periodically removes headers falling out of the retention window.
+func (s *store) pruneLoop() {
+	defer close(s.pruneDn)
+
+	ticker := time.NewTicker(DefaultPruningInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			err := s.prune(s.ctx)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				log.Errorw("pruning headers", "err", err)
+			}
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// prune removes all the headers below the retention window.
+func (s *store) prune(ctx context.Context) error {
+	from := s.tailHeight.Load()
+	if from == 0 {
+		// nothing to prune in uninitialized store
+		return nil
+	}
+	// only flushed headers are pruned, so take the head that is on disk
+	head, err := s.readHead(ctx)
+	if err != nil {
+		return err
+	}
+
+	to, err := s.pruneTarget(ctx, head, from)
+	if err != nil || from >= to {
+		return err
+	}
+
+	for from < to {
+		end := from + uint64(DefaultWriteBatchSize)
+		if end > to {
+			end = to
+		}
+
+		err = s.deleteRange(ctx, from, end)
+		if err != nil {
+			return err
+		}
+		from = end
+	}
+
+	log.Infow("pruned headers", "tail", to)
+	return nil
+}
+
+// pruneTarget calculates the new tail height for the retention window ending at the given head.
+// The head itself is never pruned.
+func (s *store) pruneTarget(ctx context.Context, head *header.ExtendedHeader, tail uint64) (uint64, error) {
+	target := uint64(head.Height)
+	if s.retention.Heights > 0 {
+		if target <= s.retention.Heights {
+			return tail, nil
+		}
+		target = target - s.retention.Heights + 1
+	}
+
+	if s.retention.Period > 0 && tail < target {
+		cutoff := head.Time.Add(-s.retention.Period)
+		// find the lowest header within the period
+		var err error
+		idx := sort.Search(int(target-tail), func(i int) bool {
+			if err != nil {
+				return true
+			}
+
+			var h *header.ExtendedHeader
+			h, err = s.readByHeight(ctx, tail+uint64(i))
+			return err == nil && !h.Time.Before(cutoff)
+		})
+		if err != nil {
+			return tail, err
+		}
+		target = tail + uint64(idx)
+	}
+
+	return target, nil
+}
+
+// deleteRange removes headers in the given range [from:to) along with their indexes
+// and moves the tail to the 'to' height.
+func (s *store) deleteRange(ctx context.Context, from, to uint64) (err error) {
+	// move the tail first, so readers get ErrPruned instead of ErrNotFound for headers being deleted
+	s.tailHeight.Store(to)
+	defer func() {
+		if err != nil {
+			// the range is still on disk, so restore the tail
+			s.tailHeight.Store(from)
+		}
+	}()
+
+	batch, err := s.ds.Batch(ctx)
+	if err != nil {
+		return err
+	}
+
+	for height := from; height < to; height++ {
+		// read directly, bypassing the caches, like readByHeight does
+		b, err := s.ds.Get(ctx, heightKey(height))
+		if err != nil {
+			if err == datastore.ErrNotFound {
+				continue
+			}
+			return err
+		}
+		hash := tmbytes.HexBytes(b)
+
+		h, err := s.readHeader(ctx, hash)
+		switch err {
+		default:
+			return err
+		case header.ErrNotFound:
+		case nil:
+			err = s.dataIndex.RemoveTo(ctx, batch, h)
+			if err != nil {
+				return err
+			}
+		}
+
+		s.cache.Remove(hash.String())
+		err = batch.Delete(ctx, datastore.NewKey(hash.String()))
+		if err != nil {
+			return err
+		}
+		// only a tiny marker is kept, so the header is reported as pruned when requested by hash
+		err = batch.Put(ctx, prunedKey(hash), nil)
+		if err != nil {
+			return err
+		}
+
+		err = s.heightIndex.RemoveTo(ctx, batch, height)
+		if err != nil {
+			return err
+		}
+
+		err = s.timeIndex.RemoveTo(ctx, batch, height)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = batch.Put(ctx, tailKey, []byte(strconv.FormatUint(to, 10)))
+	if err != nil {
+		return err
+	}
+
+	return batch.Commit(ctx)
+}
+
+// readByHeight loads the header at the given height from the datastore, bypassing the caches,
+// so that the headers about to be pruned don't evict the recent ones.
+func (s *store) readByHeight(ctx context.Context, height uint64) (*header.ExtendedHeader, error) {
+	hash, err := s.ds.Get(ctx, heightKey(height))
+	if err != nil {
+		if err == datastore.ErrNotFound {
+			return nil, header.ErrNotFound
+		}
+		return nil, err
+	}
+	return s.readHeader(ctx, hash)
+}
+
+// readHeader loads the header with the given hash from the datastore, bypassing the caches.
+func (s *store) readHeader(ctx context.Context, hash tmbytes.HexBytes) (*header.ExtendedHeader, error) {
+	b, err := s.ds.Get(ctx, datastore.NewKey(hash.String()))
+	if err != nil {
+		if err == datastore.ErrNotFound {
+			return nil, header.ErrNotFound
+		}
+		return nil, err
+	}
+	return s.unmarshalHeader(ctx, b)
+}
+
+// writeTail persists the tail height.
+func (s *store) writeTail(ctx context.Context, tail uint64) error {
+	err := s.ds.Put(ctx, tailKey, []byte(strconv.FormatUint(tail, 10)))
+	if err != nil {
+		return err
+	}
+
+	s.tailHeight.Store(tail)
+	return nil
+}
+
+// loadTail loads the tail height from the datastore.
+// Stores written before the tail was tracked get their tail discovered and persisted.
+func (s *store) loadTail(ctx context.Context) error {
+	b, err := s.ds.Get(ctx, tailKey)
+	switch err {
+	default:
+		return err
+	case nil:
+		tail, err := strconv.ParseUint(string(b), 10, 64)
+		if err != nil {
+			return err
+		}
+
+		s.tailHeight.Store(tail)
+		return nil
+	case datastore.ErrNotFound:
+	}
+
+	head, err := s.readHead(ctx)
+	switch err {
+	default:
+		return err
+	case datastore.ErrNotFound, header.ErrNotFound:
+		// the store is not initialized yet, so Init sets the tail
+		return nil
+	case nil:
+	}
+
+	// stored headers always form a contiguous range up to the head,
+	// so the lowest indexed height is the tail
+	idx := sort.Search(int(head.Height), func(i int) bool {
+		if err != nil {
+			return true
+		}
+
+		_, err = s.heightIndex.HashByHeight(ctx, uint64(i+1))
+		if err == datastore.ErrNotFound {
+			err = nil
+			return false
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.writeTail(ctx, uint64(idx+1))
+}
@@ -0,0 +1,146 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+// snapshotBatchSize defines the amount of headers requested and appended at once.
+var snapshotBatchSize uint64 = 512
+
+// Export writes the headers of the given range [from:to] from the store to the writer.
+// Each header is marshaled with header.MarshalExtendedHeader and prefixed with its uvarint length,
+// so the snapshot can be streamed back by Import.
+func Export(ctx context.Context, s header.Getter, w io.Writer, from, to uint64) (int, error) {
+	if from == 0 || from > to {
+		return 0, fmt.Errorf("header/store: invalid range [%d:%d]", from, to)
+	}
+	// ensure the range is stored, so the headers are not awaited
+	head, err := s.Head(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if to > uint64(head.Height) {
+		return 0, fmt.Errorf("header/store: range [%d:%d] exceeds the head %d", from, to, head.Height)
+	}
+
+	bw := bufio.NewWriter(w)
+	src := header.NewRangeSource(s, from, to, snapshotBatchSize)
+	var exported int
+	for {
+		h, err := src.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return exported, err
+		}
+
+		err = header.WriteDelimited(bw, h)
+		if err != nil {
+			return exported, err
+		}
+		exported++
+	}
+
+	return exported, bw.Flush()
+}
+
+// Import reads the headers written by Export from the reader and appends them to the store.
+// Every header is verified against the previous one, starting from the store's head
+// that is trusted. Headers not above the head are skipped.
+// An uninitialized store is initialized with the header of the trusted hash found in the snapshot,
+// which is then verified against.
+func Import(ctx context.Context, s header.Store, r io.Reader, trustedHash tmbytes.HexBytes) (int, error) {
+	src := NewSnapshotSource(r)
+
+	trusted, err := s.Head(ctx)
+	switch err {
+	default:
+		return 0, err
+	case header.ErrNoHead:
+		if len(trustedHash) == 0 {
+			return 0, fmt.Errorf("header/store: trusted hash is required to import into uninitialized store")
+		}
+
+		trusted, err = header.FindTrusted(ctx, src, trustedHash)
+		if err != nil {
+			return 0, err
+		}
+
+		err = s.Init(ctx, trusted)
+		if err != nil {
+			return 0, err
+		}
+	case nil:
+		if len(trustedHash) != 0 && !bytes.Equal(trusted.Hash(), trustedHash) {
+			return 0, fmt.Errorf("header/store: trusted hash %s does not match the store head %s",
+				trustedHash, trusted.Hash())
+		}
+	}
+
+	var imported int
+	batch := make([]*header.ExtendedHeader, 0, snapshotBatchSize)
+	appendBatch := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		n, err := s.Append(ctx, batch...)
+		imported += n
+		batch = batch[:0]
+		return err
+	}
+
+	for {
+		h, err := src.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return imported, err
+		}
+		if h.Height <= trusted.Height {
+			continue
+		}
+
+		err = trusted.VerifyAdjacent(h)
+		if err != nil {
+			return imported, fmt.Errorf("header/store: verifying header at height %d: %w", h.Height, err)
+		}
+		trusted = h
+
+		batch = append(batch, h)
+		if uint64(len(batch)) < snapshotBatchSize {
+			continue
+		}
+
+		err = appendBatch()
+		if err != nil {
+			return imported, err
+		}
+	}
+
+	return imported, appendBatch()
+}
+
+// snapshotSource streams the headers of the snapshot written by Export.
+type snapshotSource struct {
+	r *bufio.Reader
+}
+
+// NewSnapshotSource creates a header.Source streaming the headers of the snapshot written by Export.
+func NewSnapshotSource(r io.Reader) header.Source {
+	return &snapshotSource{r: bufio.NewReader(r)}
+}
+
+func (ss *snapshotSource) Next(context.Context) (*header.ExtendedHeader, error) {
+	return header.ReadDelimited(ss.r)
+}
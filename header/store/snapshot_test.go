@@ -0,0 +1,86 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+func TestExportImport(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	snapshotBatchSize = 3
+
+	suite := header.NewTestSuite(t, 3)
+	in := append([]*header.ExtendedHeader{suite.Head()}, suite.GenExtendedHeaders(9)...)
+
+	from := NewTestStore(ctx, t, in[0])
+	_, err := from.Append(ctx, in[1:]...)
+	require.NoError(t, err)
+	_, err = from.GetByHeight(ctx, 10)
+	require.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	n, err := Export(ctx, from, buf, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 10, n)
+	snapshot := buf.Bytes()
+
+	t.Run("InitializedStore", func(t *testing.T) {
+		to := NewTestStore(ctx, t, in[3])
+		n, err := Import(ctx, to, bytes.NewReader(snapshot), nil)
+		require.NoError(t, err)
+		assert.Equal(t, 6, n)
+
+		head, err := to.GetByHeight(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, in[9].Hash(), head.Hash())
+	})
+
+	t.Run("UninitializedStore", func(t *testing.T) {
+		s, err := NewStore(sync.MutexWrap(datastore.NewMapDatastore()))
+		require.NoError(t, err)
+		require.NoError(t, s.Start(ctx))
+		t.Cleanup(func() {
+			require.NoError(t, s.Stop(ctx))
+		})
+
+		_, err = Import(ctx, s, bytes.NewReader(snapshot), nil)
+		assert.Error(t, err)
+
+		n, err := Import(ctx, s, bytes.NewReader(snapshot), in[4].Hash())
+		require.NoError(t, err)
+		assert.Equal(t, 5, n)
+
+		tail, err := s.Tail(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, in[4].Hash(), tail.Hash())
+
+		head, err := s.GetByHeight(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, in[9].Hash(), head.Hash())
+	})
+
+	t.Run("UntrustedChain", func(t *testing.T) {
+		// the store follows another chain
+		to := NewTestStore(ctx, t, header.NewTestSuite(t, 3).Head())
+		n, err := Import(ctx, to, bytes.NewReader(snapshot), nil)
+		assert.Error(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("TruncatedSnapshot", func(t *testing.T) {
+		to := NewTestStore(ctx, t, in[0])
+		_, err := Import(ctx, to, bytes.NewReader(snapshot[:len(snapshot)-1]), nil)
+		assert.Error(t, err)
+	})
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"sync/atomic"
+	"time"
 
 	logging "github.com/ipfs/go-log/v2"
 
@@ -27,9 +30,14 @@ var (
 	// DefaultWriteBatchSize defines the size of the batched header write.
 	// Headers are written in batches not to thrash the underlying Datastore with writes.
 	DefaultWriteBatchSize = 2048
+	// DefaultPruningInterval defines how often the Store checks for headers falling out of the retention window.
+	DefaultPruningInterval = time.Minute
 )
 
 var (
+	// ErrIndexNotBuilt is returned on lookups by data hash in the store with the headers
+	// stored before the data hash index was introduced, until MigrateIndexes indexes them.
+	ErrIndexNotBuilt = errors.New("header/store: data hash index is not built, the datastore must be migrated")
 	// errStoppedStore is returned for attempted operations on a stopped store
 	errStoppedStore = errors.New("stopped store")
 )
@@ -42,14 +50,22 @@ type store struct {
 	ds datastore.Batching
 	// adaptive replacement cache of headers
 	cache *lru.ARCCache
+	// validator sets referenced by the stored headers
+	valSets *valSetStore
 
 	// header heights management
 	//
 	// maps heights to hashes
 	heightIndex *heightIndexer
+	// maps heights to times
+	timeIndex *timeIndexer
+	// maps data hashes to heights
+	dataIndex *dataIndexer
 	// manages current store read head height (1) and
 	// allows callers to wait until header for a height is stored (2)
 	heightSub *heightSub
+	// tailHeight is the lowest height kept by the store
+	tailHeight atomic.Uint64
 
 	// writing to datastore
 	//
@@ -61,18 +77,35 @@ type store struct {
 	writeHead atomic.Pointer[header.ExtendedHeader]
 	// pending keeps headers pending to be written in one batch
 	pending *batch
+
+	// pruning old headers
+	//
+	// retention defines which headers are kept by the store
+	retention RetentionPolicy
+	// signals when pruning is finished
+	pruneDn chan struct{}
+
+	// controls lifecycle for background routines
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 // NewStore constructs a Store over datastore.
 // The datastore must have a head there otherwise Start will error.
 // For first initialization of Store use NewStoreWithHead.
 func NewStore(ds datastore.Batching) (header.Store, error) {
-	return newStore(ds)
+	return newStore(ds, RetentionPolicy{})
+}
+
+// NewStoreWithRetention constructs a Store over datastore which prunes headers
+// falling out of the given RetentionPolicy in the background.
+func NewStoreWithRetention(ds datastore.Batching, retention RetentionPolicy) (header.Store, error) {
+	return newStore(ds, retention)
 }
 
 // NewStoreWithHead initiates a new Store and forcefully sets a given trusted header as head.
 func NewStoreWithHead(ctx context.Context, ds datastore.Batching, head *header.ExtendedHeader) (header.Store, error) {
-	store, err := newStore(ds)
+	store, err := newStore(ds, RetentionPolicy{})
 	if err != nil {
 		return nil, err
 	}
@@ -80,42 +113,93 @@ func NewStoreWithHead(ctx context.Context, ds datastore.Batching, head *header.E
 	return store, store.Init(ctx, head)
 }
 
-func newStore(ds datastore.Batching) (*store, error) {
+func newStore(ds datastore.Batching, retention RetentionPolicy) (*store, error) {
 	ds = namespace.Wrap(ds, storePrefix)
 	cache, err := lru.NewARC(DefaultStoreCacheSize)
 	if err != nil {
 		return nil, err
 	}
 
+	valSets, err := newValSetStore(ds)
+	if err != nil {
+		return nil, err
+	}
+
 	index, err := newHeightIndexer(ds)
 	if err != nil {
 		return nil, err
 	}
 
+	timeIndex, err := newTimeIndexer(ds)
+	if err != nil {
+		return nil, err
+	}
+
+	dataIndex, err := newDataIndexer(ds)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
 	return &store{
+		ctx:         ctx,
+		cancel:      cancel,
 		ds:          ds,
 		cache:       cache,
+		valSets:     valSets,
 		heightIndex: index,
+		timeIndex:   timeIndex,
+		dataIndex:   dataIndex,
 		heightSub:   newHeightSub(),
 		writes:      make(chan []*header.ExtendedHeader, 16),
 		writesDn:    make(chan struct{}),
 		pending:     newBatch(DefaultWriteBatchSize),
+		retention:   retention,
+		pruneDn:     make(chan struct{}),
 	}, nil
 }
 
 func (s *store) Init(ctx context.Context, initial *header.ExtendedHeader) error {
+	// check whether the store was initialized before and has its tail
+	err := s.loadTail(ctx)
+	if err != nil {
+		return err
+	}
 	// trust the given header as the initial head
-	err := s.flush(ctx, initial)
+	err = s.flush(ctx, initial)
 	if err != nil {
 		return err
 	}
 
+	// the initial header is the lowest one we have, unless the store was initialized before
+	if s.tailHeight.Load() == 0 {
+		err = s.writeTail(ctx, uint64(initial.Height))
+		if err != nil {
+			return err
+		}
+		// all the headers of the new store are indexed as they are written
+		err = s.ds.Put(ctx, indexedKey, nil)
+		if err != nil {
+			return err
+		}
+	}
+
 	log.Infow("initialized head", "height", initial.Height, "hash", initial.Hash())
 	return nil
 }
 
-func (s *store) Start(context.Context) error {
+func (s *store) Start(ctx context.Context) error {
+	err := s.loadTail(ctx)
+	if err != nil {
+		return err
+	}
+
 	go s.flushLoop()
+	if s.retention.enabled() {
+		go s.pruneLoop()
+	} else {
+		close(s.pruneDn)
+	}
 	return nil
 }
 
@@ -125,6 +209,13 @@ func (s *store) Stop(ctx context.Context) error {
 		return errStoppedStore
 	default:
 	}
+	// stop pruning before the writes, as pruning relies on the flushed head
+	s.cancel()
+	select {
+	case <-s.pruneDn:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 	// signal to prevent further writes to Store
 	s.writes <- nil
 	select {
@@ -136,6 +227,9 @@ func (s *store) Stop(ctx context.Context) error {
 	// cleanup caches
 	s.cache.Purge()
 	s.heightIndex.cache.Purge()
+	s.timeIndex.cache.Purge()
+	s.dataIndex.cache.Purge()
+	s.valSets.cache.Purge()
 	return nil
 }
 
@@ -143,6 +237,21 @@ func (s *store) Height() uint64 {
 	return s.heightSub.Height()
 }
 
+func (s *store) Tail(ctx context.Context) (*header.ExtendedHeader, error) {
+	tail := s.tailHeight.Load()
+	if tail == 0 {
+		return nil, header.ErrNoHead
+	}
+	// ensure the head is loaded, so tail is not awaited as a future height
+	if s.heightSub.Height() == 0 {
+		if _, err := s.Head(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetByHeight(ctx, tail)
+}
+
 func (s *store) Head(ctx context.Context) (*header.ExtendedHeader, error) {
 	head, err := s.GetByHeight(ctx, s.heightSub.Height())
 	if err == nil {
@@ -174,13 +283,13 @@ func (s *store) Get(ctx context.Context, hash tmbytes.HexBytes) (*header.Extende
 	b, err := s.ds.Get(ctx, datastore.NewKey(hash.String()))
 	if err != nil {
 		if err == datastore.ErrNotFound {
-			return nil, header.ErrNotFound
+			return nil, s.notFoundOrPrunedHash(ctx, hash)
 		}
 
 		return nil, err
 	}
 
-	h, err := header.UnmarshalExtendedHeader(b)
+	h, err := s.unmarshalHeader(ctx, b)
 	if err != nil {
 		return nil, err
 	}
@@ -193,6 +302,9 @@ func (s *store) GetByHeight(ctx context.Context, height uint64) (*header.Extende
 	if height == 0 {
 		return nil, fmt.Errorf("header/store: height must be bigger than zero")
 	}
+	if height < s.tailHeight.Load() {
+		return nil, header.ErrPruned
+	}
 	// if the requested 'height' was not yet published
 	// we subscribe to it
 	h, err := s.heightSub.Sub(ctx, height)
@@ -210,16 +322,25 @@ func (s *store) GetByHeight(ctx context.Context, height uint64) (*header.Extende
 	hash, err := s.heightIndex.HashByHeight(ctx, height)
 	if err != nil {
 		if err == datastore.ErrNotFound {
-			return nil, header.ErrNotFound
+			return nil, s.notFoundOrPruned(height)
 		}
 
 		return nil, err
 	}
 
-	return s.Get(ctx, hash)
+	h, err = s.Get(ctx, hash)
+	if err == header.ErrNotFound {
+		// the header might have been pruned in the meantime
+		return nil, s.notFoundOrPruned(height)
+	}
+	return h, err
 }
 
 func (s *store) GetRangeByHeight(ctx context.Context, from, to uint64) ([]*header.ExtendedHeader, error) {
+	if from < s.tailHeight.Load() {
+		return nil, header.ErrPruned
+	}
+
 	h, err := s.GetByHeight(ctx, to-1)
 	if err != nil {
 		return nil, err
@@ -230,6 +351,9 @@ func (s *store) GetRangeByHeight(ctx context.Context, from, to uint64) ([]*heade
 	for i := ln - 1; i > 0; i-- {
 		headers[i] = h
 		h, err = s.Get(ctx, h.LastHeader())
+		if err == header.ErrNotFound {
+			return nil, s.notFoundOrPruned(from)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -239,6 +363,77 @@ func (s *store) GetRangeByHeight(ctx context.Context, from, to uint64) ([]*heade
 	return headers, nil
 }
 
+func (s *store) GetByTime(
+	ctx context.Context,
+	t time.Time,
+	lookup header.TimeLookup,
+) (*header.ExtendedHeader, error) {
+	head, err := s.Head(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tail := s.tailHeight.Load()
+	if tail == 0 || tail > uint64(head.Height) {
+		return nil, header.ErrNoHead
+	}
+
+	// header times only increase with heights,
+	// so find the lowest height with time not before the given one
+	idx := sort.Search(int(uint64(head.Height)-tail+1), func(i int) bool {
+		if err != nil {
+			return true
+		}
+
+		var ht time.Time
+		ht, err = s.timeByHeight(ctx, tail+uint64(i))
+		return err == nil && !ht.Before(t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	height := tail + uint64(idx)
+
+	if height <= uint64(head.Height) {
+		h, err := s.GetByHeight(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+		if lookup == header.TimeCeiling || h.Time.Equal(t) {
+			return h, nil
+		}
+	}
+
+	switch lookup {
+	case header.TimeFloor:
+		if height == tail {
+			return nil, header.ErrNotFound
+		}
+		return s.GetByHeight(ctx, height-1)
+	case header.TimeExact, header.TimeCeiling:
+		return nil, header.ErrNotFound
+	default:
+		return nil, fmt.Errorf("header/store: unknown time lookup: %d", lookup)
+	}
+}
+
+func (s *store) GetByDataHash(ctx context.Context, hash tmbytes.HexBytes) (*header.ExtendedHeader, error) {
+	// check if the requested header is not yet written on disk
+	if h := s.pending.GetByDataHash(hash); h != nil {
+		return h, nil
+	}
+
+	height, err := s.dataIndex.HeightByDataHash(ctx, hash)
+	if err != nil {
+		if err == datastore.ErrNotFound {
+			return nil, s.notFoundOrNotIndexed(ctx)
+		}
+
+		return nil, err
+	}
+
+	return s.GetByHeight(ctx, height)
+}
+
 func (s *store) Has(ctx context.Context, hash tmbytes.HexBytes) (bool, error) {
 	if ok := s.cache.Contains(hash.String()); ok {
 		return ok, nil
@@ -309,6 +504,92 @@ func (s *store) Append(ctx context.Context, headers ...*header.ExtendedHeader) (
 	}
 }
 
+func (s *store) Prepend(ctx context.Context, headers ...*header.ExtendedHeader) (int, error) {
+	lh := len(headers)
+	if lh == 0 {
+		return 0, nil
+	}
+
+	// take current tail to verify headers against
+	tail, err := s.Tail(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// collect valid headers going down from the tail
+	verified := make([]*header.ExtendedHeader, 0, lh)
+	for i := lh - 1; i >= 0; i-- {
+		h := headers[i]
+		err = tail.VerifyPrevious(h)
+		if err != nil {
+			var verErr *header.VerifyError
+			if errors.As(err, &verErr) {
+				log.Errorw("invalid header",
+					"height_of_tail", tail.Height,
+					"hash_of_tail", tail.Hash(),
+					"height_of_invalid", h.Height,
+					"hash_of_invalid", h.Hash(),
+					"reason", verErr.Reason)
+			}
+			// if the first header is invalid, no need to go further
+			if i == lh-1 {
+				// and simply return
+				return 0, err
+			}
+			// otherwise, stop the loop and apply headers appeared to be valid
+			break
+		}
+		verified, tail = append(verified, h), h
+	}
+	// keep the error of the invalid header, if any, to return it after writing the valid ones
+	verifyErr := err
+
+	// unlike appended headers, prepended ones are written directly
+	// as they are not awaited by anyone and there is no head to maintain
+	batch, err := s.ds.Batch(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.putHeadersTo(ctx, batch, verified...)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.heightIndex.IndexTo(ctx, batch, verified...)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.timeIndex.IndexTo(ctx, batch, verified...)
+	if err != nil {
+		return 0, err
+	}
+
+	// the headers below the tail must not replace the latest heights of the shared data hashes
+	err = s.dataIndex.IndexMissingTo(ctx, batch, verified...)
+	if err != nil {
+		return 0, err
+	}
+
+	newTail := uint64(tail.Height)
+	err = batch.Put(ctx, tailKey, []byte(strconv.FormatUint(newTail, 10)))
+	if err != nil {
+		return 0, err
+	}
+
+	cerr := batch.Commit(ctx)
+	if cerr != nil {
+		return 0, cerr
+	}
+
+	s.tailHeight.Store(newTail)
+	log.Infow("new tail", "height", tail.Height, "hash", tail.Hash())
+	// we return an error here after writing,
+	// as there might be an invalid header in between of a given range
+	return len(verified), verifyErr
+}
+
 // flushLoop performs writing task to the underlying datastore in a separate routine
 // This way writes are controlled and manageable from one place allowing
 // (1) Appends not to be blocked on long disk IO writes and underlying DB compactions
@@ -359,16 +640,9 @@ func (s *store) flush(ctx context.Context, headers ...*header.ExtendedHeader) er
 	}
 
 	// collect all the headers in the batch to be written
-	for _, h := range headers {
-		b, err := h.MarshalBinary()
-		if err != nil {
-			return err
-		}
-
-		err = batch.Put(ctx, headerKey(h), b)
-		if err != nil {
-			return err
-		}
+	err = s.putHeadersTo(ctx, batch, headers...)
+	if err != nil {
+		return err
 	}
 
 	// marshal and add to batch reference to the new head
@@ -382,16 +656,79 @@ func (s *store) flush(ctx context.Context, headers ...*header.ExtendedHeader) er
 		return err
 	}
 
-	// write height indexes for headers as well
+	// write height, time and data indexes for headers as well
 	err = s.heightIndex.IndexTo(ctx, batch, headers...)
 	if err != nil {
 		return err
 	}
 
+	err = s.timeIndex.IndexTo(ctx, batch, headers...)
+	if err != nil {
+		return err
+	}
+
+	err = s.dataIndex.IndexTo(ctx, batch, headers...)
+	if err != nil {
+		return err
+	}
+
 	// finally, commit the batch on disk
 	return batch.Commit(ctx)
 }
 
+// timeByHeight loads the time of the header of the given height.
+func (s *store) timeByHeight(ctx context.Context, height uint64) (time.Time, error) {
+	// check if the requested header is not yet written on disk
+	if h := s.pending.GetByHeight(height); h != nil {
+		return h.Time, nil
+	}
+
+	t, err := s.timeIndex.TimeByHeight(ctx, height)
+	if err != datastore.ErrNotFound {
+		return t, err
+	}
+	// headers written before the time index was introduced are not indexed
+	h, err := s.GetByHeight(ctx, height)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return h.Time, nil
+}
+
+// notFoundOrPruned reports whether a missing header of the given height was pruned.
+func (s *store) notFoundOrPruned(height uint64) error {
+	if height < s.tailHeight.Load() {
+		return header.ErrPruned
+	}
+	return header.ErrNotFound
+}
+
+// notFoundOrPrunedHash returns the error for the missing header with the given hash,
+// which is header.ErrPruned if the header was pruned.
+func (s *store) notFoundOrPrunedHash(ctx context.Context, hash tmbytes.HexBytes) error {
+	pruned, err := s.ds.Has(ctx, prunedKey(hash))
+	if err != nil {
+		return err
+	}
+	if pruned {
+		return header.ErrPruned
+	}
+	return header.ErrNotFound
+}
+
+// notFoundOrNotIndexed returns the error for the header missing in the data hash index,
+// which is ErrIndexNotBuilt if the headers stored before the index was introduced are not indexed yet.
+func (s *store) notFoundOrNotIndexed(ctx context.Context) error {
+	indexed, err := s.ds.Has(ctx, indexedKey)
+	if err != nil {
+		return err
+	}
+	if !indexed {
+		return ErrIndexNotBuilt
+	}
+	return header.ErrNotFound
+}
+
 // readHead loads the head from the datastore.
 func (s *store) readHead(ctx context.Context) (*header.ExtendedHeader, error) {
 	b, err := s.ds.Get(ctx, headKey)
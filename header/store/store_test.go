@@ -12,7 +12,10 @@ import (
 
 	tmrand "github.com/tendermint/tendermint/libs/rand"
 
+	"github.com/celestiaorg/celestia-app/pkg/da"
+
 	"github.com/celestiaorg/celestia-node/header"
+	"github.com/celestiaorg/celestia-node/share"
 )
 
 func TestStore(t *testing.T) {
@@ -88,6 +91,131 @@ func TestStore(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestStoreGetByTime(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+
+	ds := sync.MutexWrap(datastore.NewMapDatastore())
+	store, err := NewStoreWithHead(ctx, ds, suite.Head())
+	require.NoError(t, err)
+
+	err = store.Start(ctx)
+	require.NoError(t, err)
+
+	in := append([]*header.ExtendedHeader{suite.Head()}, suite.GenExtendedHeaders(10)...)
+	_, err = store.Append(ctx, in[1:]...)
+	require.NoError(t, err)
+	// wait till the headers are published
+	_, err = store.GetByHeight(ctx, uint64(in[len(in)-1].Height))
+	require.NoError(t, err)
+
+	test := func(store header.Store) {
+		for i, h := range in {
+			for _, lookup := range []header.TimeLookup{header.TimeExact, header.TimeFloor, header.TimeCeiling} {
+				out, err := store.GetByTime(ctx, h.Time, lookup)
+				require.NoError(t, err)
+				assert.Equal(t, h.Hash(), out.Hash(), lookup)
+			}
+
+			between := h.Time.Add(-time.Nanosecond)
+			_, err := store.GetByTime(ctx, between, header.TimeExact)
+			assert.ErrorIs(t, err, header.ErrNotFound)
+
+			out, err := store.GetByTime(ctx, between, header.TimeCeiling)
+			require.NoError(t, err)
+			assert.Equal(t, h.Hash(), out.Hash())
+
+			out, err = store.GetByTime(ctx, between, header.TimeFloor)
+			if i == 0 {
+				assert.ErrorIs(t, err, header.ErrNotFound)
+				continue
+			}
+			require.NoError(t, err)
+			assert.Equal(t, in[i-1].Hash(), out.Hash())
+		}
+
+		after := in[len(in)-1].Time.Add(time.Hour)
+		_, err = store.GetByTime(ctx, after, header.TimeCeiling)
+		assert.ErrorIs(t, err, header.ErrNotFound)
+
+		out, err := store.GetByTime(ctx, after, header.TimeFloor)
+		require.NoError(t, err)
+		assert.Equal(t, in[len(in)-1].Hash(), out.Hash())
+	}
+	// headers are pending to be written
+	test(store)
+
+	err = store.Stop(ctx)
+	require.NoError(t, err)
+
+	// headers are flushed and indexed
+	store, err = NewStore(ds)
+	require.NoError(t, err)
+	err = store.Start(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := store.Stop(ctx)
+		require.NoError(t, err)
+	})
+	test(store)
+}
+
+func TestStoreGetByDataHash(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+
+	ds := sync.MutexWrap(datastore.NewMapDatastore())
+	store, err := NewStoreWithHead(ctx, ds, suite.Head())
+	require.NoError(t, err)
+
+	err = store.Start(ctx)
+	require.NoError(t, err)
+
+	in := make([]*header.ExtendedHeader, 5)
+	for i := range in {
+		dah := da.NewDataAvailabilityHeader(share.RandEDS(t, 2))
+		in[i] = suite.GenExtendedHeaderWithDAH(&dah)
+	}
+	_, err = store.Append(ctx, in...)
+	require.NoError(t, err)
+	// wait till the headers are published
+	_, err = store.GetByHeight(ctx, uint64(in[len(in)-1].Height))
+	require.NoError(t, err)
+
+	test := func(store header.Store) {
+		for _, h := range in {
+			out, err := store.GetByDataHash(ctx, h.DAH.Hash())
+			require.NoError(t, err)
+			assert.Equal(t, h.Hash(), out.Hash())
+		}
+
+		_, err = store.GetByDataHash(ctx, tmrand.Bytes(32))
+		assert.ErrorIs(t, err, header.ErrNotFound)
+	}
+	// headers are pending to be written
+	test(store)
+
+	err = store.Stop(ctx)
+	require.NoError(t, err)
+
+	// headers are flushed and indexed
+	store, err = NewStore(ds)
+	require.NoError(t, err)
+	err = store.Start(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := store.Stop(ctx)
+		require.NoError(t, err)
+	})
+	_, err = store.Head(ctx)
+	require.NoError(t, err)
+	test(store)
+}
+
 func TestStorePendingCacheMiss(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	t.Cleanup(cancel)
@@ -115,3 +243,175 @@ func TestStorePendingCacheMiss(t *testing.T) {
 	_, err = store.GetRangeByHeight(ctx, 101, 151)
 	require.NoError(t, err)
 }
+
+func TestStoreStopWithoutStart(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	store, err := NewStoreWithRetention(sync.MutexWrap(datastore.NewMapDatastore()), RetentionPolicy{Heights: 10})
+	require.NoError(t, err)
+	// the store never started can't finish stopping, but must not panic
+	assert.NotPanics(t, func() {
+		err = store.Stop(ctx)
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestStorePruning(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+
+	ds := sync.MutexWrap(datastore.NewMapDatastore())
+	store, err := NewStoreWithHead(ctx, ds, suite.Head())
+	require.NoError(t, err)
+
+	err = store.Start(ctx)
+	require.NoError(t, err)
+
+	tail, err := store.Tail(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, suite.Head().Hash(), tail.Hash())
+
+	in := suite.GenExtendedHeaders(20)
+	_, err = store.Append(ctx, in...)
+	require.NoError(t, err)
+
+	// stop to ensure all the headers are flushed
+	err = store.Stop(ctx)
+	require.NoError(t, err)
+
+	pstore, err := newStore(ds, RetentionPolicy{Heights: 5})
+	require.NoError(t, err)
+
+	err = pstore.Start(ctx)
+	require.NoError(t, err)
+
+	err = pstore.prune(ctx)
+	require.NoError(t, err)
+
+	tail, err = pstore.Tail(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 17, tail.Height)
+
+	_, err = pstore.GetByHeight(ctx, 16)
+	assert.ErrorIs(t, err, header.ErrPruned)
+
+	_, err = pstore.GetRangeByHeight(ctx, 10, 20)
+	assert.ErrorIs(t, err, header.ErrPruned)
+
+	ok, err := pstore.Has(ctx, in[0].Hash())
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = pstore.Get(ctx, in[0].Hash())
+	assert.ErrorIs(t, err, header.ErrPruned)
+
+	_, err = pstore.Get(ctx, tmrand.Bytes(32))
+	assert.ErrorIs(t, err, header.ErrNotFound)
+
+	// the data hash shared by all the headers is still indexed for the kept ones
+	h, err := pstore.GetByDataHash(ctx, in[0].DataHash)
+	require.NoError(t, err)
+	assert.EqualValues(t, 21, h.Height)
+
+	out, err := pstore.GetRangeByHeight(ctx, 17, 22)
+	require.NoError(t, err)
+	assert.Len(t, out, 5)
+
+	err = pstore.Stop(ctx)
+	require.NoError(t, err)
+
+	// check that the tail survives restart
+	store, err = NewStore(ds)
+	require.NoError(t, err)
+
+	err = store.Start(ctx)
+	require.NoError(t, err)
+
+	tail, err = store.Tail(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 17, tail.Height)
+
+	err = store.Stop(ctx)
+	require.NoError(t, err)
+}
+
+func TestStorePrepend(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+	in := append([]*header.ExtendedHeader{suite.Head()}, suite.GenExtendedHeaders(9)...)
+
+	ds := sync.MutexWrap(datastore.NewMapDatastore())
+	store, err := NewStoreWithHead(ctx, ds, in[9])
+	require.NoError(t, err)
+
+	err = store.Start(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := store.Stop(ctx)
+		require.NoError(t, err)
+	})
+
+	// the headers above the invalid one are still written
+	invalid := *in[4]
+	invalid.ChainID = "invalid"
+	prepend := append(append(append([]*header.ExtendedHeader{}, in[:4]...), &invalid), in[5:9]...)
+	ln, err := store.Prepend(ctx, prepend...)
+	var verErr *header.VerifyError
+	assert.ErrorAs(t, err, &verErr)
+	assert.Equal(t, 4, ln)
+
+	tail, err := store.Tail(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, in[5].Hash(), tail.Hash())
+
+	// the invalid header is not written, so the valid one can be prepended instead
+	ln, err = store.Prepend(ctx, in[:5]...)
+	require.NoError(t, err)
+	assert.Equal(t, 5, ln)
+
+	out, err := store.GetRangeByHeight(ctx, 1, 11)
+	require.NoError(t, err)
+	for i, h := range in {
+		assert.Equal(t, h.Hash(), out[i].Hash())
+	}
+}
+
+func TestStorePrependDataHash(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+	// the headers of empty blocks after genesis share the data hash, while the one in the middle has its own
+	in := append([]*header.ExtendedHeader{suite.Head()}, suite.GenExtendedHeaders(4)...)
+	dah := da.NewDataAvailabilityHeader(share.RandEDS(t, 2))
+	in = append(in, suite.GenExtendedHeaderWithDAH(&dah))
+	in = append(in, suite.GenExtendedHeaders(4)...)
+
+	ds := sync.MutexWrap(datastore.NewMapDatastore())
+	store, err := NewStoreWithHead(ctx, ds, in[9])
+	require.NoError(t, err)
+
+	err = store.Start(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := store.Stop(ctx)
+		require.NoError(t, err)
+	})
+
+	_, err = store.Prepend(ctx, in[:9]...)
+	require.NoError(t, err)
+
+	// the latest header is kept for the shared data hash
+	h, err := store.GetByDataHash(ctx, in[1].DataHash)
+	require.NoError(t, err)
+	assert.Equal(t, in[9].Hash(), h.Hash())
+
+	h, err = store.GetByDataHash(ctx, in[5].DataHash)
+	require.NoError(t, err)
+	assert.Equal(t, in[5].Hash(), h.Hash())
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/ipfs/go-datastore"
+
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+// timeIndexer stores and caches mappings between header Height and Time.
+// Header times only increase with heights, so the compact mapping is enough
+// to binary search headers by time without loading and unmarshalling them.
+type timeIndexer struct {
+	ds    datastore.Batching
+	cache *lru.ARCCache
+}
+
+// newTimeIndexer creates new timeIndexer.
+func newTimeIndexer(ds datastore.Batching) (*timeIndexer, error) {
+	cache, err := lru.NewARC(DefaultIndexCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &timeIndexer{
+		ds:    ds,
+		cache: cache,
+	}, nil
+}
+
+// TimeByHeight loads a header time corresponding to the given height.
+func (ti *timeIndexer) TimeByHeight(ctx context.Context, h uint64) (time.Time, error) {
+	if v, ok := ti.cache.Get(h); ok {
+		return v.(time.Time), nil
+	}
+
+	val, err := ti.ds.Get(ctx, timeKey(h))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(val) != 8 {
+		return time.Time{}, fmt.Errorf("header/store: malformed time index at height %d", h)
+	}
+
+	t := time.Unix(0, int64(binary.BigEndian.Uint64(val))).UTC()
+	ti.cache.Add(h, t)
+	return t, nil
+}
+
+// IndexTo saves mapping between header Height and Time to the given batch.
+func (ti *timeIndexer) IndexTo(ctx context.Context, batch datastore.Batch, headers ...*header.ExtendedHeader) error {
+	for _, h := range headers {
+		val := make([]byte, 8)
+		binary.BigEndian.PutUint64(val, uint64(h.Time.UnixNano()))
+		err := batch.Put(ctx, timeKey(uint64(h.Height)), val)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveTo removes mapping between header Height and Time to the given batch.
+func (ti *timeIndexer) RemoveTo(ctx context.Context, batch datastore.Batch, h uint64) error {
+	ti.cache.Remove(h)
+	return batch.Delete(ctx, timeKey(h))
+}
@@ -0,0 +1,162 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/ipfs/go-datastore"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+
+	"github.com/celestiaorg/celestia-node/header"
+	header_pb "github.com/celestiaorg/celestia-node/header/pb"
+)
+
+// DefaultValSetCacheSize defines the amount of max entries allowed in the validator set cache.
+var DefaultValSetCacheSize = 64
+
+// valSetStore stores validator sets once, keyed by their hash.
+// Validator sets rarely change between heights, so headers are stored
+// referencing their set by ValidatorsHash instead of embedding it.
+// Sets are kept when headers are pruned, as they are few and can be shared with the kept headers.
+type valSetStore struct {
+	ds    datastore.Batching
+	cache *lru.ARCCache
+}
+
+// newValSetStore creates new valSetStore.
+func newValSetStore(ds datastore.Batching) (*valSetStore, error) {
+	cache, err := lru.NewARC(DefaultValSetCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &valSetStore{
+		ds:    ds,
+		cache: cache,
+	}, nil
+}
+
+// Get loads the validator set by its hash.
+func (vs *valSetStore) Get(ctx context.Context, hash tmbytes.HexBytes) (*tmproto.ValidatorSet, error) {
+	if v, ok := vs.cache.Get(hash.String()); ok {
+		return v.(*tmproto.ValidatorSet), nil
+	}
+
+	b, err := vs.ds.Get(ctx, valSetKey(hash))
+	if err != nil {
+		if err == datastore.ErrNotFound {
+			return nil, fmt.Errorf("header/store: missing validator set %s", hash)
+		}
+		return nil, err
+	}
+
+	set := &tmproto.ValidatorSet{}
+	err = set.Unmarshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	vs.cache.Add(hash.String(), set)
+	return set, nil
+}
+
+// PutTo saves the validator set to the given batch, unless it is stored already.
+// The written map tracks the sets saved within the batch.
+func (vs *valSetStore) PutTo(
+	ctx context.Context,
+	batch datastore.Batch,
+	written map[string]struct{},
+	hash tmbytes.HexBytes,
+	set *tmproto.ValidatorSet,
+) error {
+	if _, ok := written[hash.String()]; ok {
+		return nil
+	}
+	if !vs.cache.Contains(hash.String()) {
+		ok, err := vs.ds.Has(ctx, valSetKey(hash))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			b, err := set.Marshal()
+			if err != nil {
+				return err
+			}
+
+			err = batch.Put(ctx, valSetKey(hash), b)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	written[hash.String()] = struct{}{}
+	return nil
+}
+
+// putHeadersTo saves the given headers to the batch, storing their validator sets separately.
+func (s *store) putHeadersTo(ctx context.Context, batch datastore.Batch, headers ...*header.ExtendedHeader) error {
+	written := make(map[string]struct{})
+	for _, h := range headers {
+		pb, err := header.ExtendedHeaderToProto(h)
+		if err != nil {
+			return err
+		}
+
+		err = s.putHeaderTo(ctx, batch, written, headerKey(h), pb)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// putHeaderTo saves the header in its protobuf form to the batch under the given key,
+// storing its validator set separately.
+func (s *store) putHeaderTo(
+	ctx context.Context,
+	batch datastore.Batch,
+	written map[string]struct{},
+	key datastore.Key,
+	pb *header_pb.ExtendedHeader,
+) error {
+	err := s.valSets.PutTo(ctx, batch, written, pb.Header.ValidatorsHash, pb.ValidatorSet)
+	if err != nil {
+		return err
+	}
+
+	// the set is referenced by the header's ValidatorsHash
+	pb.ValidatorSet = nil
+	b, err := pb.Marshal()
+	if err != nil {
+		return err
+	}
+
+	return batch.Put(ctx, key, b)
+}
+
+// unmarshalHeader deserializes the stored header, rehydrating its validator set.
+// Headers stored before the validator sets were deduplicated embed the set.
+func (s *store) unmarshalHeader(ctx context.Context, b []byte) (*header.ExtendedHeader, error) {
+	pb := &header_pb.ExtendedHeader{}
+	err := pb.Unmarshal(b)
+	if err != nil {
+		return nil, err
+	}
+	if pb.Header == nil {
+		return nil, fmt.Errorf("header/store: stored header is missing its RawHeader")
+	}
+
+	if pb.ValidatorSet == nil {
+		pb.ValidatorSet, err = s.valSets.Get(ctx, pb.Header.ValidatorsHash)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// ProtoToExtendedHeader ensures the validator set matches the header's ValidatorsHash
+	return header.ProtoToExtendedHeader(pb)
+}
@@ -0,0 +1,283 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/ipfs/go-datastore/query"
+
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+// ErrConflict is returned when the Syncer is halted due to detected conflicting headers.
+var ErrConflict = errors.New("header/sync: conflicting headers detected")
+
+var conflictPrefix = datastore.NewKey("header_conflicts")
+
+// Sources of conflicting headers.
+const (
+	SourceGossip   = "gossip"
+	SourceExchange = "exchange"
+)
+
+// Conflict is the evidence of two different valid headers at the same height signed by the same
+// validator set, meaning that either the chain forked or the node is being attacked.
+type Conflict struct {
+	// Known is the header known before the conflict, either stored or pending to be stored.
+	Known *header.ExtendedHeader
+	// Conflicting is the header received from the network that conflicts with the known one.
+	Conflicting *header.ExtendedHeader
+	// Source is where the conflicting header was received from.
+	Source string
+	// Detected is the time the conflict was detected.
+	Detected time.Time
+}
+
+// Height returns the height of the conflicting headers.
+func (c *Conflict) Height() uint64 {
+	return uint64(c.Known.Height)
+}
+
+// conflictRecord is the persisted form of Conflict.
+type conflictRecord struct {
+	Known       []byte    `json:"known"`
+	Conflicting []byte    `json:"conflicting"`
+	Source      string    `json:"source"`
+	Detected    time.Time `json:"detected"`
+}
+
+// WithDatastore persists the evidence of detected conflicts in the given datastore,
+// so the Syncer stays halted across restarts, as well as the history of syncs.
+func WithDatastore(ds datastore.Datastore) Option {
+	return func(s *Syncer) {
+		s.ds = namespace.Wrap(ds, conflictPrefix)
+		s.historyDs = namespace.Wrap(ds, historyPrefix)
+	}
+}
+
+// WithConflictHandler sets the handler called once conflicting headers are detected,
+// e.g. to notify the network.
+func WithConflictHandler(handle func(context.Context, *Conflict)) Option {
+	return func(s *Syncer) {
+		s.onConflict = handle
+	}
+}
+
+// Conflicts returns the evidence of the detected conflicts if any.
+func (s *Syncer) Conflicts() []*Conflict {
+	s.conflictsLk.RLock()
+	defer s.conflictsLk.RUnlock()
+	return append([]*Conflict(nil), s.conflicts...)
+}
+
+// halted reports whether the Syncer is halted due to a conflict.
+func (s *Syncer) halted() bool {
+	s.conflictsLk.RLock()
+	defer s.conflictsLk.RUnlock()
+	return len(s.conflicts) != 0
+}
+
+// checkConflict cross-checks the header with the known one at the same height, if any.
+// It halts the Syncer and returns ErrConflict if the headers conflict.
+func (s *Syncer) checkConflict(ctx context.Context, h *header.ExtendedHeader, source string) error {
+	known, err := s.knownHeader(ctx, uint64(h.Height))
+	if err != nil {
+		log.Errorw("getting known header to check for conflicts", "height", h.Height, "err", err)
+		return nil
+	}
+	if known == nil {
+		return nil
+	}
+	return s.conflict(ctx, known, h, source)
+}
+
+// checkLinks ensures the headers received via gossip and from the exchange form a single hash chain
+// with the store's head. A broken link means that either side might conflict with the other,
+// so both sides are cross-checked with the exchange.
+func (s *Syncer) checkLinks(ctx context.Context, headers []*header.ExtendedHeader) error {
+	prev, err := s.store.Head(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, h := range headers {
+		if h.Height == prev.Height+1 && !bytes.Equal(h.LastHeader(), prev.Hash()) {
+			log.Warnw("received headers do not link",
+				"height", h.Height, "hash", h.Hash(), "prev_hash", prev.Hash())
+			for _, eh := range []*header.ExtendedHeader{prev, h} {
+				err = s.crossCheck(ctx, eh)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		prev = h
+	}
+	return nil
+}
+
+// crossCheck requests the header at the same height from the exchange and checks
+// whether it conflicts with the given one.
+func (s *Syncer) crossCheck(ctx context.Context, h *header.ExtendedHeader) error {
+	eh, err := s.exchange.GetByHeight(ctx, uint64(h.Height))
+	if err != nil {
+		log.Debugw("requesting header to check for conflicts", "height", h.Height, "err", err)
+		return nil
+	}
+	return s.conflict(ctx, h, eh, SourceExchange)
+}
+
+// conflict halts the Syncer and returns ErrConflict if the received header
+// is a valid header conflicting with the known one.
+func (s *Syncer) conflict(ctx context.Context, known, h *header.ExtendedHeader, source string) error {
+	if bytes.Equal(known.Hash(), h.Hash()) {
+		return nil
+	}
+	// only a header signed by the same validator set as the known one is an evidence,
+	// otherwise anybody could halt the Syncer by crafting a header with own validators
+	if !bytes.Equal(known.ValidatorsHash, h.ValidatorsHash) {
+		log.Warnw("received header at known height signed by unknown validators",
+			"height", h.Height, "hash", h.Hash(), "source", source)
+		return nil
+	}
+	if err := h.ValidateBasic(); err != nil {
+		log.Warnw("received invalid header at known height",
+			"height", h.Height, "hash", h.Hash(), "source", source, "err", err)
+		return nil
+	}
+
+	s.halt(ctx, &Conflict{
+		Known:       known,
+		Conflicting: h,
+		Source:      source,
+		Detected:    time.Now(),
+	})
+	return ErrConflict
+}
+
+// knownHeader returns the header at the given height either from pending headers or the store.
+// It returns nil if there is no header known at the height.
+func (s *Syncer) knownHeader(ctx context.Context, height uint64) (*header.ExtendedHeader, error) {
+	if h := s.pending.Get(height); h != nil {
+		return h, nil
+	}
+	if height > s.store.Height() {
+		return nil, nil
+	}
+
+	h, err := s.store.GetByHeight(ctx, height)
+	switch err {
+	case nil:
+		return h, nil
+	case header.ErrPruned, header.ErrNotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// halt stops syncing and keeps the evidence of the conflict.
+func (s *Syncer) halt(ctx context.Context, c *Conflict) {
+	s.conflictsLk.Lock()
+	s.conflicts = append(s.conflicts, c)
+	s.conflictsLk.Unlock()
+
+	log.Errorw("conflicting headers detected, syncing is halted",
+		"height", c.Height(),
+		"known_hash", c.Known.Hash(),
+		"conflicting_hash", c.Conflicting.Hash(),
+		"source", c.Source)
+
+	if err := s.storeConflict(ctx, c); err != nil {
+		log.Errorw("storing conflicting headers", "height", c.Height(), "err", err)
+	}
+	if s.onConflict != nil {
+		s.onConflict(ctx, c)
+	}
+	// stop all the syncing routines
+	s.cancel()
+}
+
+// storeConflict persists the conflict if the datastore is set.
+func (s *Syncer) storeConflict(ctx context.Context, c *Conflict) error {
+	if s.ds == nil {
+		return nil
+	}
+
+	known, err := c.Known.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	conflicting, err := c.Conflicting.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	bs, err := json.Marshal(&conflictRecord{
+		Known:       known,
+		Conflicting: conflicting,
+		Source:      c.Source,
+		Detected:    c.Detected,
+	})
+	if err != nil {
+		return err
+	}
+	return s.ds.Put(ctx, datastore.NewKey(strconv.FormatUint(c.Height(), 10)), bs)
+}
+
+// loadConflicts loads the conflicts persisted before if the datastore is set.
+func (s *Syncer) loadConflicts(ctx context.Context) error {
+	if s.ds == nil {
+		return nil
+	}
+
+	results, err := s.ds.Query(ctx, query.Query{})
+	if err != nil {
+		return err
+	}
+	entries, err := results.Rest()
+	if err != nil {
+		return err
+	}
+
+	conflicts := make([]*Conflict, 0, len(entries))
+	for _, entry := range entries {
+		var rec conflictRecord
+		err = json.Unmarshal(entry.Value, &rec)
+		if err != nil {
+			return err
+		}
+
+		c := &Conflict{
+			Known:       new(header.ExtendedHeader),
+			Conflicting: new(header.ExtendedHeader),
+			Source:      rec.Source,
+			Detected:    rec.Detected,
+		}
+		err = c.Known.UnmarshalBinary(rec.Known)
+		if err != nil {
+			return err
+		}
+		err = c.Conflicting.UnmarshalBinary(rec.Conflicting)
+		if err != nil {
+			return err
+		}
+		conflicts = append(conflicts, c)
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Height() < conflicts[j].Height()
+	})
+
+	s.conflictsLk.Lock()
+	s.conflicts = conflicts
+	s.conflictsLk.Unlock()
+	return nil
+}
@@ -0,0 +1,212 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+)
+
+// DefaultHistorySize is the default amount of the latest syncs kept in the history.
+var DefaultHistorySize = 100
+
+var historyPrefix = datastore.NewKey("header_sync_history")
+
+// WithHistorySize sets the amount of the latest syncs kept in the history.
+// Sizes below 1 are ignored, keeping the DefaultHistorySize.
+func WithHistorySize(size int) Option {
+	return func(s *Syncer) {
+		if size < 1 {
+			log.Warnw("ignoring invalid sync history size", "size", size, "default", DefaultHistorySize)
+			return
+		}
+		s.historySize = size
+	}
+}
+
+// History returns the states of the finished syncs, oldest first.
+// The history is kept across restarts if the datastore is set.
+func (s *Syncer) History() []State {
+	s.stateLk.RLock()
+	defer s.stateLk.RUnlock()
+	return append([]State(nil), s.history...)
+}
+
+// recordHistory adds the state of the finished sync to the history,
+// evicting the oldest ones beyond the history size.
+func (s *Syncer) recordHistory(ctx context.Context, state State) {
+	state = state.withProgress(state.End)
+
+	s.stateLk.Lock()
+	s.history = append(s.history, state)
+	var evicted []State
+	if over := len(s.history) - s.historySize; over > 0 {
+		evicted = append(evicted, s.history[:over]...)
+		s.history = append([]State(nil), s.history[over:]...)
+	}
+	s.stateLk.Unlock()
+
+	if s.historyDs == nil {
+		return
+	}
+	bs, err := json.Marshal(state)
+	if err == nil {
+		err = s.historyDs.Put(ctx, historyKey(state.ID), bs)
+	}
+	if err != nil {
+		log.Errorw("storing sync history", "id", state.ID, "err", err)
+	}
+	for _, st := range evicted {
+		err = s.historyDs.Delete(ctx, historyKey(st.ID))
+		if err != nil {
+			log.Errorw("evicting sync history", "id", st.ID, "err", err)
+		}
+	}
+}
+
+// loadHistory loads the history persisted before if the datastore is set
+// and continues the IDs of syncs from the latest one.
+func (s *Syncer) loadHistory(ctx context.Context) error {
+	if s.historyDs == nil {
+		return nil
+	}
+
+	results, err := s.historyDs.Query(ctx, query.Query{})
+	if err != nil {
+		return err
+	}
+	entries, err := results.Rest()
+	if err != nil {
+		return err
+	}
+
+	history := make([]State, 0, len(entries))
+	for _, entry := range entries {
+		var state State
+		err = json.Unmarshal(entry.Value, &state)
+		if err != nil {
+			return err
+		}
+		history = append(history, state)
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].ID < history[j].ID
+	})
+	// the history size might have been decreased since it was persisted
+	if over := len(history) - s.historySize; over > 0 {
+		for _, st := range history[:over] {
+			err = s.historyDs.Delete(ctx, historyKey(st.ID))
+			if err != nil {
+				return err
+			}
+		}
+		history = history[over:]
+	}
+
+	s.stateLk.Lock()
+	s.history = history
+	if len(history) > 0 {
+		s.state.ID = history[len(history)-1].ID
+	}
+	s.stateLk.Unlock()
+	return nil
+}
+
+func historyKey(id uint64) datastore.Key {
+	return datastore.NewKey(strconv.FormatUint(id, 10))
+}
+
+// withProgress fills in the rate of the sync and the estimated time left until it is finished,
+// as of the given moment.
+func (s State) withProgress(now time.Time) State {
+	s.Rate, s.ETA = 0, 0
+	if s.Start.IsZero() || s.Height < s.FromHeight {
+		return s
+	}
+
+	elapsed := now.Sub(s.Start)
+	if !s.End.IsZero() {
+		elapsed = s.Duration()
+	}
+	if elapsed <= 0 {
+		return s
+	}
+
+	synced := s.Height
+	if synced > s.ToHeight {
+		// headers received after the sync are not part of it
+		synced = s.ToHeight
+	}
+	s.Rate = float64(synced-s.FromHeight+1) / elapsed.Seconds()
+	if s.End.IsZero() && !s.Finished() {
+		s.ETA = time.Duration(float64(s.ToHeight-s.Height) / s.Rate * float64(time.Second))
+	}
+	return s
+}
+
+// stateJSON is the JSON form of State with the error kept as a message.
+type stateJSON struct {
+	ID         uint64           `json:"id"`
+	Height     uint64           `json:"height"`
+	FromHeight uint64           `json:"from_height"`
+	ToHeight   uint64           `json:"to_height"`
+	FromHash   tmbytes.HexBytes `json:"from_hash"`
+	ToHash     tmbytes.HexBytes `json:"to_hash"`
+	Start      time.Time        `json:"start"`
+	End        time.Time        `json:"end"`
+	Error      string           `json:"error,omitempty"`
+	Rate       float64          `json:"rate"`
+	ETA        time.Duration    `json:"eta"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s State) MarshalJSON() ([]byte, error) {
+	out := stateJSON{
+		ID:         s.ID,
+		Height:     s.Height,
+		FromHeight: s.FromHeight,
+		ToHeight:   s.ToHeight,
+		FromHash:   s.FromHash,
+		ToHash:     s.ToHash,
+		Start:      s.Start,
+		End:        s.End,
+		Rate:       s.Rate,
+		ETA:        s.ETA,
+	}
+	if s.Error != nil {
+		out.Error = s.Error.Error()
+	}
+	return json.Marshal(&out)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *State) UnmarshalJSON(data []byte) error {
+	var in stateJSON
+	err := json.Unmarshal(data, &in)
+	if err != nil {
+		return err
+	}
+
+	*s = State{
+		ID:         in.ID,
+		Height:     in.Height,
+		FromHeight: in.FromHeight,
+		ToHeight:   in.ToHeight,
+		FromHash:   in.FromHash,
+		ToHash:     in.ToHash,
+		Start:      in.Start,
+		End:        in.End,
+		Rate:       in.Rate,
+		ETA:        in.ETA,
+	}
+	if in.Error != "" {
+		s.Error = errors.New(in.Error)
+	}
+	return nil
+}
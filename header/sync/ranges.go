@@ -62,6 +62,19 @@ func (rs *ranges) Add(h *header.ExtendedHeader) {
 	}
 }
 
+// Get returns the cached ExtendedHeader at the given height if any.
+func (rs *ranges) Get(height uint64) *header.ExtendedHeader {
+	rs.lk.RLock()
+	defer rs.lk.RUnlock()
+
+	for _, r := range rs.ranges {
+		if h := r.Get(height); h != nil {
+			return h
+		}
+	}
+	return nil
+}
+
 // FirstRangeWithin checks if the first range is within a given height span [start:end]
 // and returns it.
 func (rs *ranges) FirstRangeWithin(start, end uint64) (*headerRange, bool) {
@@ -134,6 +147,16 @@ func (r *headerRange) Head() *header.ExtendedHeader {
 	return r.headers[ln-1]
 }
 
+// Get returns the header at the given height if the range contains it.
+func (r *headerRange) Get(height uint64) *header.ExtendedHeader {
+	r.lk.RLock()
+	defer r.lk.RUnlock()
+	if height < r.start || height-r.start >= uint64(len(r.headers)) {
+		return nil
+	}
+	return r.headers[height-r.start]
+}
+
 // Before truncates all the headers before height 'end' - [r.Start:end]
 func (r *headerRange) Before(end uint64) ([]*header.ExtendedHeader, uint64) {
 	r.lk.Lock()
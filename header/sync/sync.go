@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ipfs/go-datastore"
 	logging "github.com/ipfs/go-log/v2"
 	tmbytes "github.com/tendermint/tendermint/libs/bytes"
 
@@ -40,39 +41,137 @@ type Syncer struct {
 	// blockTime provides a reference point for the Syncer to determine
 	// whether its subjective head is outdated
 	blockTime time.Duration
+	// netBlockTime follows the block time of the network, if set
+	netBlockTime func() time.Duration
+	// netTrustingPeriod follows the trusting period of the network, if set
+	netTrustingPeriod func() time.Duration
 
 	// stateLk protects state which represents the current or latest sync
-	stateLk sync.RWMutex
-	state   State
+	// and history which keeps the states of the latest finished syncs
+	stateLk     sync.RWMutex
+	state       State
+	history     []State
+	historySize int
+	// historyDs persists history, if set
+	historyDs datastore.Datastore
 	// signals to start syncing
 	triggerSync chan struct{}
 	// pending keeps ranges of valid new network headers awaiting to be appended to store
 	pending ranges
 	// netReqLk ensures only one network head is requested at any moment
 	netReqLk sync.RWMutex
+	// backward enables syncing of headers below the store's tail
+	backward bool
+	// bisection enables skipping verification of network heads
+	bisection bool
+	// trustedHash and trustedHeight are the optional checkpoint
+	// the head from subjective initialization is checked against
+	trustedHash   tmbytes.HexBytes
+	trustedHeight uint64
+	// initQuorum is the amount of trusted peers required to agree on the head
+	// during automatic subjective initialization, if set
+	initQuorum int
+
+	// conflictsLk protects conflicts which keep the evidence of detected conflicting headers
+	conflictsLk sync.RWMutex
+	conflicts   []*Conflict
+	// ds persists conflicts, if set
+	ds datastore.Datastore
+	// onConflict is called once conflicting headers are detected, if set
+	onConflict func(context.Context, *Conflict)
 
 	// controls lifecycle for syncLoop
 	ctx    context.Context
 	cancel context.CancelFunc
 }
 
+// Option configures optional Syncer behaviour.
+type Option func(*Syncer)
+
+// WithBackwardSync enables syncing of headers below the Store's tail down to the genesis.
+// Useful for nodes initialized from a recent trusted header which still need the history.
+func WithBackwardSync() Option {
+	return func(s *Syncer) {
+		s.backward = true
+	}
+}
+
+// WithTrustedCheckpoint sets the checkpoint the network head received during automatic subjective
+// initialization is checked against. The head must not be below the trusted height and,
+// if the trusted hash is given, must be verifiable from the header with this hash.
+// Either value can be omitted.
+func WithTrustedCheckpoint(hash tmbytes.HexBytes, height uint64) Option {
+	return func(s *Syncer) {
+		s.trustedHash = hash
+		s.trustedHeight = height
+	}
+}
+
+// WithInitQuorum enables strict automatic subjective initialization, so the network head is only accepted
+// once at least the given amount of trusted peers agree on it. The Exchange must implement header.QuorumExchange.
+// Routine network head requests are not affected.
+func WithInitQuorum(quorum int) Option {
+	return func(s *Syncer) {
+		s.initQuorum = quorum
+	}
+}
+
+// WithBlockTime makes the Syncer follow the block time of the network as it changes,
+// instead of the one it is created with.
+func WithBlockTime(blockTime func() time.Duration) Option {
+	return func(s *Syncer) {
+		s.netBlockTime = blockTime
+	}
+}
+
+// WithTrustingPeriod makes the Syncer check its subjective head for expiration against the trusting period
+// of the network as it changes, instead of header.TrustingPeriod.
+func WithTrustingPeriod(trustingPeriod func() time.Duration) Option {
+	return func(s *Syncer) {
+		s.netTrustingPeriod = trustingPeriod
+	}
+}
+
 // NewSyncer creates a new instance of Syncer.
-func NewSyncer(exchange header.Exchange, store header.Store, sub header.Subscriber, blockTime time.Duration) *Syncer {
-	return &Syncer{
+func NewSyncer(
+	exchange header.Exchange,
+	store header.Store,
+	sub header.Subscriber,
+	blockTime time.Duration,
+	opts ...Option,
+) *Syncer {
+	s := &Syncer{
 		sub:         sub,
 		exchange:    exchange,
 		store:       store,
 		blockTime:   blockTime,
 		triggerSync: make(chan struct{}, 1), // should be buffered
+		historySize: DefaultHistorySize,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // Start starts the syncing routine.
 func (s *Syncer) Start(ctx context.Context) error {
 	s.ctx, s.cancel = context.WithCancel(context.Background())
+	// the Syncer stays halted until the evidence of conflicting headers is resolved by the operator
+	err := s.loadConflicts(ctx)
+	if err != nil {
+		return err
+	}
+	if s.halted() {
+		return ErrConflict
+	}
+	err = s.loadHistory(ctx)
+	if err != nil {
+		return err
+	}
 	// register validator for header subscriptions
 	// syncer does not subscribe itself and syncs headers together with validation
-	err := s.sub.AddValidator(s.incomingNetHead)
+	err = s.sub.AddValidator(s.incomingNetHead)
 	if err != nil {
 		return err
 	}
@@ -83,6 +182,9 @@ func (s *Syncer) Start(ctx context.Context) error {
 	}
 	// start syncLoop only if Start is errorless
 	go s.syncLoop()
+	if s.backward {
+		go s.syncBackward(s.ctx)
+	}
 	return nil
 }
 
@@ -111,7 +213,9 @@ type State struct {
 	FromHeight, ToHeight uint64 // the starting and the ending point of a sync
 	FromHash, ToHash     tmbytes.HexBytes
 	Start, End           time.Time
-	Error                error // the error that might happen within a sync
+	Error                error         // the error that might happen within a sync
+	Rate                 float64       // amount of headers synced per second
+	ETA                  time.Duration // estimated time left until the sync is finished, if in progress
 }
 
 // Finished returns true if sync is done, false otherwise.
@@ -132,7 +236,23 @@ func (s *Syncer) State() State {
 	state := s.state
 	s.stateLk.RUnlock()
 	state.Height = s.store.Height()
-	return state
+	return state.withProgress(time.Now())
+}
+
+// getBlockTime returns the block time of the network.
+func (s *Syncer) getBlockTime() time.Duration {
+	if s.netBlockTime != nil {
+		return s.netBlockTime()
+	}
+	return s.blockTime
+}
+
+// getTrustingPeriod returns the trusting period of the network.
+func (s *Syncer) getTrustingPeriod() time.Duration {
+	if s.netTrustingPeriod != nil {
+		return s.netTrustingPeriod()
+	}
+	return header.TrustingPeriod
 }
 
 // wantSync will trigger the syncing loop (non-blocking).
@@ -211,6 +331,8 @@ func (s *Syncer) doSync(ctx context.Context, fromHead, toHead *header.ExtendedHe
 	s.state.FromHash = fromHead.Hash()
 	s.state.ToHash = toHead.Hash()
 	s.state.Start = time.Now()
+	s.state.End = time.Time{}
+	s.state.Error = nil
 	s.stateLk.Unlock()
 
 	for processed := 0; from < to; from += uint64(processed) {
@@ -221,9 +343,16 @@ func (s *Syncer) doSync(ctx context.Context, fromHead, toHead *header.ExtendedHe
 	}
 
 	s.stateLk.Lock()
+	s.state.Height = from - 1
 	s.state.End = time.Now()
 	s.state.Error = err
+	state := s.state
 	s.stateLk.Unlock()
+
+	// syncs interrupted by stopping the Syncer are not finished
+	if !errors.Is(err, context.Canceled) {
+		s.recordHistory(ctx, state)
+	}
 	return err
 }
 
@@ -234,7 +363,26 @@ func (s *Syncer) processHeaders(ctx context.Context, from, to uint64) (int, erro
 		return 0, err
 	}
 
-	return s.store.Append(ctx, headers...)
+	err = s.checkLinks(ctx, headers)
+	if err != nil {
+		return 0, err
+	}
+
+	ln, err := s.store.Append(ctx, headers...)
+	var verErr *header.VerifyError
+	if errors.As(err, &verErr) && ln < len(headers) {
+		// the headers are appended up to the first invalid one
+		s.reportInvalid(headers[ln])
+	}
+	return ln, err
+}
+
+// reportInvalid reports the header failed verification to the Exchange, if supported,
+// so that the peer it was received from gets punished.
+func (s *Syncer) reportInvalid(h *header.ExtendedHeader) {
+	if reporter, ok := s.exchange.(header.InvalidReporter); ok {
+		reporter.ReportInvalid(h)
+	}
 }
 
 // TODO(@Wondertan): Number of headers that can be requested at once. Either make this configurable or,
@@ -242,7 +390,10 @@ func (s *Syncer) processHeaders(ctx context.Context, from, to uint64) (int, erro
 //	find a proper rationale for constant.
 //
 // TODO(@Wondertan): Make configurable
-var requestSize uint64 = 512
+//
+// NOTE: Exchange may split the request into smaller chunks requested from multiple peers in parallel,
+// so the size should be big enough to benefit from that.
+var requestSize uint64 = 2048
 
 // findHeaders gets headers from either remote peers or from local cache of headers received by PubSub - [from:to]
 func (s *Syncer) findHeaders(ctx context.Context, from, to uint64) ([]*header.ExtendedHeader, error) {
@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+// backwardRetryInterval is the time to wait before retrying a failed backward sync request.
+var backwardRetryInterval = time.Second * 10
+
+// syncBackward syncs headers below the Store's tail down to the genesis.
+// Headers are requested in ranges and each range is verified by the hash chain
+// against the tail, which is already trusted.
+func (s *Syncer) syncBackward(ctx context.Context) {
+	for {
+		tail, err := s.store.Tail(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+
+			log.Errorw("getting tail during backward sync", "err", err)
+			if !s.waitBackwardRetry(ctx) {
+				return
+			}
+			continue
+		}
+
+		if tail.Height <= 1 {
+			log.Info("finished backward sync")
+			return
+		}
+
+		// request the range right below the tail - [from:to)
+		from, to := uint64(1), uint64(tail.Height)
+		if to-from > requestSize {
+			from = to - requestSize
+		}
+
+		log.Debugw("syncing headers backward", "from", from, "to", to-1)
+		headers, err := s.exchange.GetRangeByHeight(ctx, from, to-from)
+		if err == nil {
+			var ln int
+			ln, err = s.store.Prepend(ctx, headers...)
+			var verErr *header.VerifyError
+			if errors.As(err, &verErr) && ln < len(headers) {
+				// the headers are prepended down to the first invalid one
+				s.reportInvalid(headers[len(headers)-1-ln])
+			}
+		}
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+
+			log.Errorw("syncing headers backward", "from", from, "to", to-1, "err", err)
+			if !s.waitBackwardRetry(ctx) {
+				return
+			}
+		}
+	}
+}
+
+// waitBackwardRetry blocks until backward sync can be retried and reports whether it should.
+func (s *Syncer) waitBackwardRetry(ctx context.Context) bool {
+	select {
+	case <-time.After(backwardRetryInterval):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
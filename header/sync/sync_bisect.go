@@ -0,0 +1,113 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tendermint/tendermint/types"
+
+	"github.com/celestiaorg/celestia-node/header"
+)
+
+// maxBisectionRequests limits the amount of pivot headers requested to verify a single network head.
+var maxBisectionRequests = 128
+
+// WithBisection enables skipping verification of network heads requested from trusted peers.
+// A head too far ahead to be verified by the subjective head directly, due to validator set changes in between,
+// gets verified through a minimal set of pivot headers instead, while the headers in between are synced later on.
+func WithBisection() Option {
+	return func(s *Syncer) {
+		s.bisection = true
+	}
+}
+
+// bisect verifies the network head against the subjective head through pivot headers
+// and sets the verified pivots as the subjective heads, so that the network head can be validated
+// against the latest of them.
+// It is a no-op if the network head can be verified directly.
+func (s *Syncer) bisect(ctx context.Context, netHead *header.ExtendedHeader) {
+	sbjHead, err := s.subjectiveHead(ctx)
+	if err != nil {
+		log.Errorw("getting subjective head during bisection", "err", err)
+		return
+	}
+	if !sbjHead.IsBefore(netHead) {
+		return
+	}
+
+	pivots, err := s.verifySkipping(ctx, sbjHead, netHead)
+	if err != nil {
+		log.Errorw("bisecting network head",
+			"height_of_head", netHead.Height,
+			"height_of_subjective", sbjHead.Height,
+			"err", err)
+		return
+	}
+	if len(pivots) != 0 {
+		log.Infow("network head verified by bisection",
+			"height_of_head", netHead.Height,
+			"height_of_subjective", sbjHead.Height,
+			"pivots", len(pivots))
+	}
+	for _, pivot := range pivots {
+		s.pending.Add(pivot)
+	}
+}
+
+// verifySkipping verifies the untrusted header against the trusted one in the manner of
+// Tendermint's light client skipping verification. Whenever the trusted validators can't vouch
+// for the untrusted header, the gap is bisected and the pivot header in the middle gets verified first,
+// becoming the new trusted header once valid.
+// It returns the pivot headers the untrusted header was verified through in ascending order.
+func (s *Syncer) verifySkipping(
+	ctx context.Context,
+	trusted, untrst *header.ExtendedHeader,
+) ([]*header.ExtendedHeader, error) {
+	var (
+		pivots []*header.ExtendedHeader
+		// headers awaiting verification with the lowest one on top
+		stack    = []*header.ExtendedHeader{untrst}
+		requests int
+	)
+	for len(stack) != 0 {
+		next := stack[len(stack)-1]
+		err := verify(trusted, next)
+		if err == nil {
+			trusted, stack = next, stack[:len(stack)-1]
+			if len(stack) != 0 {
+				pivots = append(pivots, next)
+			}
+			continue
+		}
+		// only the lack of trusted voting power can be overcome by bisection
+		if !types.IsErrNotEnoughVotingPowerSigned(err) {
+			s.reportInvalid(next)
+			return nil, err
+		}
+
+		if requests == maxBisectionRequests {
+			return nil, fmt.Errorf("exceeded %d pivot requests", maxBisectionRequests)
+		}
+		requests++
+
+		height := trusted.Height + (next.Height-trusted.Height)/2
+		pivot, err := s.exchange.GetByHeight(ctx, uint64(height))
+		if err != nil {
+			return nil, fmt.Errorf("requesting pivot header: %w", err)
+		}
+		if pivot.Height != height {
+			return nil, fmt.Errorf("requested pivot header at height %d, got %d", height, pivot.Height)
+		}
+		stack = append(stack, pivot)
+	}
+	return pivots, nil
+}
+
+// verify verifies the untrusted header against the trusted one
+// using adjacent verification for adjacent headers.
+func verify(trusted, untrst *header.ExtendedHeader) error {
+	if untrst.Height == trusted.Height+1 {
+		return trusted.VerifyAdjacent(untrst)
+	}
+	return trusted.VerifyNonAdjacent(untrst)
+}
@@ -1,14 +1,20 @@
 package sync
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 
 	"github.com/celestiaorg/celestia-node/header"
 )
 
+// ErrUntrustedHead is returned when the network head received during automatic subjective initialization
+// does not match the trusted checkpoint.
+var ErrUntrustedHead = errors.New("header/sync: network head does not match trusted checkpoint")
+
 // Head returns the Syncer's latest known header. It calls 'networkHead' in order to
 // either return or eagerly fetch the most recent header.
 func (s *Syncer) Head(ctx context.Context) (*header.ExtendedHeader, error) {
@@ -16,7 +22,8 @@ func (s *Syncer) Head(ctx context.Context) (*header.ExtendedHeader, error) {
 }
 
 // subjectiveHead returns the latest known local header that is not expired(within trusting period).
-// If the header is expired, it is retrieved from a trusted peer without validation;
+// If the header is expired, it is retrieved from a trusted peer without validation,
+// except for the check against the trusted checkpoint, if any;
 // in other words, an automatic subjective initialization is performed.
 func (s *Syncer) subjectiveHead(ctx context.Context) (*header.ExtendedHeader, error) {
 	// pending head is the latest known subjective head Syncer syncs to, so try to get it
@@ -33,12 +40,17 @@ func (s *Syncer) subjectiveHead(ctx context.Context) (*header.ExtendedHeader, er
 		return nil, err
 	}
 	// check if our subjective header is not expired and use it
-	if !netHead.IsExpired() {
+	if !netHead.IsExpired(s.getTrustingPeriod()) {
 		return netHead, nil
 	}
 	log.Infow("subjective header expired", "height", netHead.Height)
 	// otherwise, request network head from a trusted peer
-	netHead, err = s.exchange.Head(ctx)
+	netHead, err = s.initHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// ensure the head does not contradict the checkpoint the operator trusts
+	err = s.checkTrusted(ctx, netHead)
 	if err != nil {
 		return nil, err
 	}
@@ -49,15 +61,56 @@ func (s *Syncer) subjectiveHead(ctx context.Context) (*header.ExtendedHeader, er
 	default:
 		log.Infow("subjective initialization finished", "height", netHead.Height)
 		return netHead, nil
-	case netHead.IsExpired():
+	case netHead.IsExpired(s.getTrustingPeriod()):
 		log.Warnw("subjective initialization with an expired header", "height", netHead.Height)
-	case !netHead.IsRecent(s.blockTime):
+	case !netHead.IsRecent(s.getBlockTime()):
 		log.Warnw("subjective initialization with an old header", "height", netHead.Height)
 	}
 	log.Warn("trusted peer is out of sync")
 	return netHead, nil
 }
 
+// initHead requests the network head for automatic subjective initialization,
+// requiring the quorum of trusted peers to agree on it, if set.
+func (s *Syncer) initHead(ctx context.Context) (*header.ExtendedHeader, error) {
+	if s.initQuorum <= 0 {
+		return s.exchange.Head(ctx)
+	}
+	ex, ok := s.exchange.(header.QuorumExchange)
+	if !ok {
+		return nil, fmt.Errorf("%w: exchange does not support head quorum", header.ErrNoQuorum)
+	}
+	return ex.HeadWithQuorum(ctx, s.initQuorum)
+}
+
+// checkTrusted checks the network head against the trusted checkpoint, if set.
+func (s *Syncer) checkTrusted(ctx context.Context, netHead *header.ExtendedHeader) error {
+	if uint64(netHead.Height) < s.trustedHeight {
+		return fmt.Errorf("%w: head %d is below trusted height %d", ErrUntrustedHead, netHead.Height, s.trustedHeight)
+	}
+	if len(s.trustedHash) == 0 || bytes.Equal(netHead.Hash(), s.trustedHash) {
+		return nil
+	}
+
+	trusted, err := s.exchange.Get(ctx, s.trustedHash)
+	if err != nil {
+		return fmt.Errorf("requesting trusted header %s: %w", s.trustedHash, err)
+	}
+	if s.trustedHeight != 0 && uint64(trusted.Height) != s.trustedHeight {
+		return fmt.Errorf("%w: trusted header %s is at height %d instead of %d",
+			ErrUntrustedHead, s.trustedHash, trusted.Height, s.trustedHeight)
+	}
+	if !trusted.IsBefore(netHead) {
+		return fmt.Errorf("%w: head %d is not above trusted header %d", ErrUntrustedHead, netHead.Height, trusted.Height)
+	}
+	// the head must be signed by enough of the validators the operator trusts
+	err = trusted.VerifyNonAdjacent(netHead)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUntrustedHead, err)
+	}
+	return nil
+}
+
 // networkHead returns the latest network header.
 // Known subjective head is considered network head if it is recent enough(now-timestamp<=blocktime).
 // Otherwise, network header is requested from a trusted peer and set as the new subjective head,
@@ -68,7 +121,7 @@ func (s *Syncer) networkHead(ctx context.Context) (*header.ExtendedHeader, error
 		return nil, err
 	}
 	// if subjective header is recent enough (relative to the network's block time) - just use it
-	if sbjHead.IsRecent(s.blockTime) {
+	if sbjHead.IsRecent(s.getBlockTime()) {
 		return sbjHead, nil
 	}
 	// otherwise, request head from a trusted peer, as we assume it is fully synced
@@ -90,6 +143,15 @@ func (s *Syncer) networkHead(ctx context.Context) (*header.ExtendedHeader, error
 	if err != nil {
 		return nil, err
 	}
+	err = s.checkConflict(ctx, netHead, SourceExchange)
+	if err != nil {
+		return nil, err
+	}
+	// the subjective head might be unable to verify netHead directly, if validators changed too much since,
+	// so verify it through the pivot headers in between first
+	if s.bisection {
+		s.bisect(ctx, netHead)
+	}
 	// process netHead returned from the trusted peer and validate against the subjective head
 	// NOTE: We could trust the netHead like we do during 'automatic subjective initialization'
 	// but in this case our subjective head is not expired, so we should verify maybeHead
@@ -102,6 +164,13 @@ func (s *Syncer) networkHead(ctx context.Context) (*header.ExtendedHeader, error
 
 // incomingNetHead processes new gossiped network headers.
 func (s *Syncer) incomingNetHead(ctx context.Context, netHead *header.ExtendedHeader) pubsub.ValidationResult {
+	if s.halted() {
+		return pubsub.ValidationIgnore
+	}
+	// cross-check the header with the known one at the same height first
+	if err := s.checkConflict(ctx, netHead, SourceGossip); err != nil {
+		return pubsub.ValidationIgnore
+	}
 	// Try to short-circuit netHead with append. If not adjacent/from future - try it as new network header
 	_, err := s.store.Append(ctx, netHead)
 	if err == nil {
@@ -169,6 +238,7 @@ func (s *Syncer) validate(ctx context.Context, new *header.ExtendedHeader) pubsu
 			"height_of_subjective", sbjHead.Height,
 			"hash_of_subjective", sbjHead.Hash(),
 			"reason", verErr.Reason)
+		s.reportInvalid(new)
 		return pubsub.ValidationReject
 	}
 	// and accept if the header is good
@@ -2,9 +2,13 @@ package sync
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -59,6 +63,108 @@ func TestSyncSimpleRequestingHead(t *testing.T) {
 	assert.True(t, state.Finished(), state)
 }
 
+func TestSyncTrustedCheckpoint(t *testing.T) {
+	// this way we force local head of Syncer to expire, so it performs subjective initialization
+	header.TrustingPeriod = time.Microsecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+	head := suite.Head()
+
+	remoteStore := store.NewTestStore(ctx, t, head)
+	_, err := remoteStore.Append(ctx, suite.GenExtendedHeaders(100)...)
+	require.NoError(t, err)
+	trusted, err := remoteStore.GetByHeight(ctx, 50)
+	require.NoError(t, err)
+	_, err = remoteStore.GetByHeight(ctx, 101)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		hash   bytes.HexBytes
+		height uint64
+		err    error
+	}{
+		{name: "Hash", hash: trusted.Hash()},
+		{name: "HashAndHeight", hash: trusted.Hash(), height: uint64(trusted.Height)},
+		{name: "Height", height: 101},
+		{name: "AboveHead", height: 200, err: ErrUntrustedHead},
+		{name: "HeightMismatch", hash: trusted.Hash(), height: 40, err: ErrUntrustedHead},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			localStore := store.NewTestStore(ctx, t, head)
+			syncer := NewSyncer(local.NewExchange(remoteStore), localStore, &header.DummySubscriber{}, blockTime,
+				WithTrustedCheckpoint(tt.hash, tt.height))
+			err := syncer.Start(ctx)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				syncer.Stop(ctx) //nolint:errcheck
+			})
+			// the network head got accepted, so the Syncer syncs up to it
+			_, err = localStore.GetByHeight(ctx, 101)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSyncInitQuorum(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+	head := suite.Head()
+
+	remoteStore := store.NewTestStore(ctx, t, head)
+	_, err := remoteStore.Append(ctx, suite.GenExtendedHeaders(100)...)
+	require.NoError(t, err)
+
+	t.Run("Init", func(t *testing.T) {
+		// this way we force local head of Syncer to expire, so it performs subjective initialization
+		header.TrustingPeriod = time.Microsecond
+
+		exchange := &exchangeWithQuorum{Exchange: local.NewExchange(remoteStore)}
+		syncer := NewSyncer(exchange, store.NewTestStore(ctx, t, head), &header.DummySubscriber{}, blockTime,
+			WithInitQuorum(2))
+		err := syncer.Start(ctx)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			syncer.Stop(ctx) //nolint:errcheck
+		})
+		assert.Equal(t, []int{2}, exchange.quorums)
+	})
+
+	t.Run("Routine", func(t *testing.T) {
+		// the local head is not expired, so the network head is requested routinely
+		header.TrustingPeriod = time.Minute
+
+		exchange := &exchangeWithQuorum{Exchange: local.NewExchange(remoteStore)}
+		syncer := NewSyncer(exchange, store.NewTestStore(ctx, t, head), &header.DummySubscriber{}, blockTime,
+			WithInitQuorum(2))
+		err := syncer.Start(ctx)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			syncer.Stop(ctx) //nolint:errcheck
+		})
+		assert.Empty(t, exchange.quorums)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		header.TrustingPeriod = time.Microsecond
+
+		syncer := NewSyncer(local.NewExchange(remoteStore), store.NewTestStore(ctx, t, head),
+			&header.DummySubscriber{}, blockTime, WithInitQuorum(2))
+		err := syncer.Start(ctx)
+		assert.ErrorIs(t, err, header.ErrNoQuorum)
+	})
+}
+
 func TestSyncCatchUp(t *testing.T) {
 	// just set a big enough value, so we trust local header and don't request anything
 	header.TrustingPeriod = time.Minute
@@ -104,6 +210,148 @@ func TestSyncCatchUp(t *testing.T) {
 	assert.True(t, state.Finished(), state)
 }
 
+func TestSyncHistory(t *testing.T) {
+	// this way we force local head of Syncer to expire, so it requests a new one from trusted peer
+	header.TrustingPeriod = time.Microsecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+	head := suite.Head()
+
+	remoteStore := store.NewTestStore(ctx, t, head)
+	localStore := store.NewTestStore(ctx, t, head)
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	newSyncer := func() *Syncer {
+		return NewSyncer(local.NewExchange(remoteStore), localStore, &header.DummySubscriber{}, blockTime,
+			WithDatastore(ds),
+			WithHistorySize(2),
+		)
+	}
+
+	// every start syncs up to the grown network head and records the sync
+	for i := 1; i <= 3; i++ {
+		_, err := remoteStore.Append(ctx, suite.GenExtendedHeaders(10)...)
+		require.NoError(t, err)
+		_, err = remoteStore.GetByHeight(ctx, uint64(i*10+1))
+		require.NoError(t, err)
+
+		syncer := newSyncer()
+		require.NoError(t, syncer.Start(ctx))
+		require.Eventually(t, func() bool {
+			return syncer.State().ID == uint64(i) && len(syncer.History()) == min(i, 2) &&
+				syncer.History()[min(i, 2)-1].ID == uint64(i)
+		}, time.Second, time.Millisecond*10)
+
+		state := syncer.State()
+		assert.True(t, state.Finished(), state)
+		assert.Greater(t, state.Rate, float64(0))
+		assert.Zero(t, state.ETA)
+
+		last := syncer.History()[min(i, 2)-1]
+		assert.Equal(t, uint64(i*10+1), last.Height)
+		assert.Equal(t, uint64((i-1)*10+2), last.FromHeight)
+		assert.Equal(t, uint64(i*10+1), last.ToHeight)
+		assert.NoError(t, last.Error)
+		assert.Greater(t, last.Rate, float64(0))
+		require.NoError(t, syncer.Stop(ctx))
+	}
+
+	// only the latest syncs are kept
+	syncer := newSyncer()
+	require.NoError(t, syncer.loadHistory(ctx))
+	history := syncer.History()
+	require.Len(t, history, 2)
+	assert.Equal(t, uint64(2), history[0].ID)
+	assert.Equal(t, uint64(3), history[1].ID)
+	assert.Equal(t, uint64(3), syncer.State().ID)
+}
+
+func TestSyncHistoryInvalidSize(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+	localStore := store.NewTestStore(ctx, t, suite.Head())
+	for _, size := range []int{0, -1} {
+		syncer := NewSyncer(local.NewExchange(localStore), localStore, &header.DummySubscriber{}, blockTime,
+			WithHistorySize(size))
+		assert.Equal(t, DefaultHistorySize, syncer.historySize)
+
+		// recording the history does not panic
+		syncer.recordHistory(ctx, State{ID: 1})
+		assert.Len(t, syncer.History(), 1)
+	}
+}
+
+func TestStateProgress(t *testing.T) {
+	start := time.Now()
+	state := State{
+		ID:         1,
+		Height:     30,
+		FromHeight: 11,
+		ToHeight:   110,
+		FromHash:   bytes.HexBytes{0x01},
+		ToHash:     bytes.HexBytes{0x02},
+		Start:      start,
+	}
+
+	// 20 headers in 2 seconds with 80 left
+	state = state.withProgress(start.Add(time.Second * 2))
+	assert.Equal(t, float64(10), state.Rate)
+	assert.Equal(t, time.Second*8, state.ETA)
+
+	state.Height, state.End = 60, start.Add(time.Second*10)
+	state.Error = errors.New("stopped")
+	state = state.withProgress(start.Add(time.Minute))
+	assert.Equal(t, float64(5), state.Rate)
+	assert.Zero(t, state.ETA)
+
+	bs, err := json.Marshal(state)
+	require.NoError(t, err)
+	var out State
+	require.NoError(t, json.Unmarshal(bs, &out))
+	assert.Equal(t, state.Error.Error(), out.Error.Error())
+	out.Error = state.Error
+	assert.True(t, state.Start.Equal(out.Start))
+	assert.True(t, state.End.Equal(out.End))
+	out.Start, out.End = state.Start, state.End
+	assert.Equal(t, state, out)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func TestSyncReportInvalid(t *testing.T) {
+	header.TrustingPeriod = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+	head := suite.Head()
+
+	remoteStore := store.NewTestStore(ctx, t, head)
+	_, err := remoteStore.Append(ctx, suite.GenExtendedHeaders(5)...)
+	require.NoError(t, err)
+
+	localStore := store.NewTestStore(ctx, t, head)
+	exchange := &exchangeReportingInvalid{Exchange: local.NewExchange(remoteStore)}
+	syncer := NewSyncer(exchange, localStore, &header.DummySubscriber{}, blockTime)
+
+	ln, err := syncer.processHeaders(ctx, 2, 6)
+	var verErr *header.VerifyError
+	require.ErrorAs(t, err, &verErr)
+	assert.Equal(t, 4, ln)
+	require.Len(t, exchange.reported, 1)
+	assert.EqualValues(t, 6, exchange.reported[0].Height)
+}
+
 func TestSyncPendingRangesWithMisses(t *testing.T) {
 	// just set a big enough value, so we trust local header and don't request anything
 	header.TrustingPeriod = time.Minute
@@ -159,6 +407,197 @@ func TestSyncPendingRangesWithMisses(t *testing.T) {
 	assert.Empty(t, syncer.pending.Head()) // assert all cache from pending is used
 }
 
+func TestSyncBackward(t *testing.T) {
+	// just set a big enough value, so we trust local header and don't request anything
+	header.TrustingPeriod = time.Minute
+	requestSize = 13 // just some random number
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+	remoteStore := store.NewTestStore(ctx, t, suite.Head())
+	in := suite.GenExtendedHeaders(100)
+	_, err := remoteStore.Append(ctx, in...)
+	require.NoError(t, err)
+
+	// initialize local store from a recent trusted header
+	localStore := store.NewTestStore(ctx, t, in[len(in)-1])
+	syncer := NewSyncer(
+		local.NewExchange(remoteStore),
+		localStore,
+		&header.DummySubscriber{},
+		blockTime,
+		WithBackwardSync(),
+	)
+	err = syncer.Start(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := syncer.Stop(ctx)
+		require.NoError(t, err)
+	})
+
+	require.Eventually(t, func() bool {
+		tail, err := localStore.Tail(ctx)
+		return err == nil && tail.Height == 1
+	}, time.Second*3, time.Millisecond*10)
+
+	exp, err := remoteStore.GetRangeByHeight(ctx, 1, 102)
+	require.NoError(t, err)
+	have, err := localStore.GetRangeByHeight(ctx, 1, 102)
+	require.NoError(t, err)
+	for i := range exp {
+		assert.True(t, exp[i].Equals(have[i]))
+	}
+}
+
+func TestSyncConflict(t *testing.T) {
+	// just set a big enough value, so we trust local header and don't request anything
+	header.TrustingPeriod = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+	head := suite.Head()
+	in := suite.GenExtendedHeaders(20)
+
+	remoteStore := store.NewTestStore(ctx, t, head)
+	_, err := remoteStore.Append(ctx, in...)
+	require.NoError(t, err)
+	localStore := store.NewTestStore(ctx, t, head)
+	_, err = localStore.Append(ctx, in[:9]...)
+	require.NoError(t, err)
+	_, err = localStore.GetByHeight(ctx, uint64(in[8].Height))
+	require.NoError(t, err)
+
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	newSyncer := func(handled chan *Conflict) *Syncer {
+		return NewSyncer(local.NewExchange(remoteStore), localStore, &header.DummySubscriber{}, blockTime,
+			WithDatastore(ds),
+			WithConflictHandler(func(_ context.Context, c *Conflict) {
+				handled <- c
+			}),
+		)
+	}
+
+	t.Run("GossipWithStored", func(t *testing.T) {
+		handled := make(chan *Conflict, 1)
+		syncer := newSyncer(handled)
+		err := syncer.Start(ctx)
+		require.NoError(t, err)
+
+		conflicting := suite.GenConflictingExtendedHeader(in[4])
+		res := syncer.incomingNetHead(ctx, conflicting)
+		assert.Equal(t, pubsub.ValidationIgnore, res)
+		// once halted, even good headers are ignored
+		res = syncer.incomingNetHead(ctx, in[9])
+		assert.Equal(t, pubsub.ValidationIgnore, res)
+
+		conflicts := syncer.Conflicts()
+		require.Len(t, conflicts, 1)
+		assert.Equal(t, SourceGossip, conflicts[0].Source)
+		assert.True(t, in[4].Equals(conflicts[0].Known))
+		assert.True(t, conflicting.Equals(conflicts[0].Conflicting))
+		assert.Equal(t, conflicts[0], <-handled)
+		require.NoError(t, syncer.Stop(ctx))
+
+		// the evidence survives restarts, keeping the Syncer halted
+		syncer = newSyncer(handled)
+		err = syncer.Start(ctx)
+		require.ErrorIs(t, err, ErrConflict)
+		conflicts = syncer.Conflicts()
+		require.Len(t, conflicts, 1)
+		assert.True(t, conflicting.Equals(conflicts[0].Conflicting))
+		require.NoError(t, syncer.Stop(ctx))
+	})
+
+	t.Run("ExchangeWithPending", func(t *testing.T) {
+		ds = dssync.MutexWrap(datastore.NewMapDatastore())
+		handled := make(chan *Conflict, 1)
+		syncer := newSyncer(handled)
+		err := syncer.Start(ctx)
+		require.NoError(t, err)
+
+		// the header received via gossip conflicts with the one served by the exchange,
+		// which is noticed once the following headers from the exchange do not link to it
+		conflicting := suite.GenConflictingExtendedHeader(in[14])
+		syncer.pending.Add(conflicting)
+		syncer.pending.Add(in[16])
+		syncer.sync(ctx)
+
+		conflicts := syncer.Conflicts()
+		require.Len(t, conflicts, 1)
+		assert.Equal(t, SourceExchange, conflicts[0].Source)
+		assert.True(t, conflicting.Equals(conflicts[0].Known))
+		assert.True(t, in[14].Equals(conflicts[0].Conflicting))
+		assert.Equal(t, conflicts[0], <-handled)
+		res := syncer.incomingNetHead(ctx, in[16])
+		assert.Equal(t, pubsub.ValidationIgnore, res)
+		require.NoError(t, syncer.Stop(ctx))
+	})
+}
+
+func TestSyncBisection(t *testing.T) {
+	header.TrustingPeriod = time.Minute
+	blockTime := time.Nanosecond // so that we always request the network head
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 4)
+	head := suite.Head()
+
+	remoteStore := store.NewTestStore(ctx, t, head)
+	// entirely replace the validators twice, so that the genesis validators can't vouch for the network head
+	for i, amount := range []int{20, 30, 50} {
+		if i != 0 {
+			suite.ReplaceValidators(4)
+		}
+		_, err := remoteStore.Append(ctx, suite.GenExtendedHeaders(amount)...)
+		require.NoError(t, err)
+	}
+	netHead, err := remoteStore.GetByHeight(ctx, 101)
+	require.NoError(t, err)
+	require.Error(t, head.VerifyNonAdjacent(netHead))
+
+	t.Run("Disabled", func(t *testing.T) {
+		localStore := store.NewTestStore(ctx, t, head)
+		syncer := NewSyncer(local.NewExchange(remoteStore), localStore, &header.DummySubscriber{}, blockTime)
+		err := syncer.Start(ctx)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			syncer.Stop(ctx) //nolint:errcheck
+		})
+		// the network head gets rejected
+		assert.Nil(t, syncer.pending.Head())
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		localStore := store.NewTestStore(ctx, t, head)
+		syncer := NewSyncer(local.NewExchange(remoteStore), localStore, &header.DummySubscriber{}, blockTime,
+			WithBisection())
+
+		pivots, err := syncer.verifySkipping(ctx, head, netHead)
+		require.NoError(t, err)
+		require.NotEmpty(t, pivots)
+		// far less headers than the whole range are needed
+		assert.Less(t, len(pivots), 20)
+		for i := 1; i < len(pivots); i++ {
+			assert.True(t, pivots[i-1].IsBefore(pivots[i]))
+		}
+
+		err = syncer.Start(ctx)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			syncer.Stop(ctx) //nolint:errcheck
+		})
+		// the network head gets verified and the headers in between are synced
+		_, err = localStore.GetByHeight(ctx, 101)
+		require.NoError(t, err)
+	})
+}
+
 // Test that only one objective header is requested at a time
 func TestSyncer_OnlyOneRecentRequest(t *testing.T) {
 	blockTime := time.Nanosecond // so that we always request recent
@@ -215,3 +654,39 @@ func (e *exchangeCountingHead) GetByHeight(ctx context.Context, u uint64) (*head
 func (e *exchangeCountingHead) GetRangeByHeight(c context.Context, from, to uint64) ([]*header.ExtendedHeader, error) {
 	panic("implement me")
 }
+
+// exchangeWithQuorum records the quorums the head is requested with.
+type exchangeWithQuorum struct {
+	header.Exchange
+	quorums []int
+}
+
+func (e *exchangeWithQuorum) HeadWithQuorum(ctx context.Context, quorum int) (*header.ExtendedHeader, error) {
+	e.quorums = append(e.quorums, quorum)
+	return e.Head(ctx)
+}
+
+// exchangeReportingInvalid serves the last header of the requested range invalid
+// and records the headers reported as invalid.
+type exchangeReportingInvalid struct {
+	header.Exchange
+	reported []*header.ExtendedHeader
+}
+
+func (e *exchangeReportingInvalid) GetRangeByHeight(
+	ctx context.Context,
+	from, amount uint64,
+) ([]*header.ExtendedHeader, error) {
+	headers, err := e.Exchange.GetRangeByHeight(ctx, from, amount)
+	if err != nil || len(headers) == 0 {
+		return headers, err
+	}
+	invalid := *headers[len(headers)-1]
+	invalid.ChainID = "invalid"
+	headers[len(headers)-1] = &invalid
+	return headers, nil
+}
+
+func (e *exchangeReportingInvalid) ReportInvalid(h *header.ExtendedHeader) {
+	e.reported = append(e.reported, h)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/celestiaorg/celestia-node/share"
 
 	mrand "math/rand"
+	"sort"
 	"testing"
 	"time"
 
@@ -36,6 +37,10 @@ type TestSuite struct {
 	valSet  *types.ValidatorSet
 	valPntr int
 
+	// validators taking over after the next header, if any
+	nextVals   []types.PrivValidator
+	nextValSet *types.ValidatorSet
+
 	head *ExtendedHeader
 }
 
@@ -54,13 +59,10 @@ func (s *TestSuite) genesis() *ExtendedHeader {
 	gen.ValidatorsHash = s.valSet.Hash()
 	gen.NextValidatorsHash = s.valSet.Hash()
 	gen.Height = 1
-	voteSet := types.NewVoteSet(gen.ChainID, gen.Height, 0, tmproto.PrecommitType, s.valSet)
-	commit, err := core.MakeCommit(RandBlockID(s.t), gen.Height, 0, voteSet, s.vals, time.Now())
-	require.NoError(s.t, err)
 	dah := EmptyDAH()
 	eh := &ExtendedHeader{
 		RawHeader:    *gen,
-		Commit:       commit,
+		Commit:       s.Commit(gen),
 		ValidatorSet: s.valSet,
 		DAH:          &dah,
 	}
@@ -90,18 +92,63 @@ func (s *TestSuite) GenExtendedHeader() *ExtendedHeader {
 	}
 
 	dah := da.MinDataAvailabilityHeader()
+	return s.GenExtendedHeaderWithDAH(&dah)
+}
+
+// GenExtendedHeaderWithDAH generates the next valid ExtendedHeader committing to the given DAH.
+func (s *TestSuite) GenExtendedHeaderWithDAH(dah *da.DataAvailabilityHeader) *ExtendedHeader {
 	height := s.Head().Height + 1
 	rh := s.GenRawHeader(height, s.Head().Hash(), s.Head().Commit.Hash(), dah.Hash())
 	s.head = &ExtendedHeader{
 		RawHeader:    *rh,
 		Commit:       s.Commit(rh),
 		ValidatorSet: s.valSet,
-		DAH:          &dah,
+		DAH:          dah,
 	}
 	require.NoError(s.t, s.head.ValidateBasic())
+	if s.nextValSet != nil {
+		s.vals, s.valSet = s.nextVals, s.nextValSet
+		s.nextVals, s.nextValSet = nil, nil
+	}
 	return s.head
 }
 
+// ReplaceValidators replaces the given amount of validators with new ones.
+// The next generated header commits to the new validators,
+// which sign the headers following it.
+func (s *TestSuite) ReplaceValidators(num int) {
+	require.LessOrEqual(s.t, num, len(s.vals))
+	vals := make([]types.PrivValidator, 0, len(s.vals))
+	valz := make([]*types.Validator, 0, len(s.vals))
+	for i, val := range s.vals[num:] {
+		vals = append(vals, val)
+		valz = append(valz, s.valSet.Validators[num+i].Copy())
+	}
+	for i := 0; i < num; i++ {
+		val, pv := core.RandValidator(false, s.valSet.Validators[0].VotingPower)
+		vals = append(vals, pv)
+		valz = append(valz, val)
+	}
+	// ordering must match the validator set's one, as all validators have the same power
+	sort.Sort(types.PrivValidatorsByAddress(vals))
+	s.nextVals, s.nextValSet = vals, types.NewValidatorSet(valz)
+}
+
+// GenConflictingExtendedHeader generates a valid ExtendedHeader at the same height
+// and signed by the same validators as the given one, but with a different hash.
+func (s *TestSuite) GenConflictingExtendedHeader(eh *ExtendedHeader) *ExtendedHeader {
+	rh := eh.RawHeader
+	rh.Time = rh.Time.Add(time.Millisecond)
+	conflicting := &ExtendedHeader{
+		RawHeader:    rh,
+		Commit:       s.Commit(&rh),
+		ValidatorSet: eh.ValidatorSet,
+		DAH:          eh.DAH,
+	}
+	require.NoError(s.t, conflicting.ValidateBasic())
+	return conflicting
+}
+
 func (s *TestSuite) GenRawHeader(
 	height int64, lastHeader, lastCommit, dataHash bytes.HexBytes) *RawHeader {
 	rh := RandRawHeader(s.t)
@@ -112,6 +159,9 @@ func (s *TestSuite) GenRawHeader(
 	rh.DataHash = dataHash
 	rh.ValidatorsHash = s.valSet.Hash()
 	rh.NextValidatorsHash = s.valSet.Hash()
+	if s.nextValSet != nil {
+		rh.NextValidatorsHash = s.nextValSet.Hash()
+	}
 	rh.ProposerAddress = s.nextProposer().Address
 	return rh
 }
@@ -0,0 +1,200 @@
+package header
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+)
+
+// Source provides an ordered stream of untrusted ExtendedHeaders, e.g. read from a file,
+// requested over RPC or loaded from another node's Store.
+type Source interface {
+	// Next returns the next ExtendedHeader of the stream or io.EOF once the stream is exhausted.
+	Next(context.Context) (*ExtendedHeader, error)
+}
+
+// Verifier verifies an ordered stream of untrusted ExtendedHeaders against a trusted one without
+// running a node. Every verified header becomes trusted to verify the following one.
+type Verifier struct {
+	trusted        *ExtendedHeader
+	trustingPeriod time.Duration
+}
+
+// VerifierOption configures the Verifier.
+type VerifierOption func(*Verifier)
+
+// WithTrustingPeriod sets the period through which a header's validator set is trusted
+// to verify non-adjacent headers. Defaults to TrustingPeriod.
+func WithTrustingPeriod(period time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.trustingPeriod = period
+	}
+}
+
+// NewVerifier creates a new Verifier starting from the given trusted ExtendedHeader.
+func NewVerifier(trusted *ExtendedHeader, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		trusted:        trusted,
+		trustingPeriod: TrustingPeriod,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Trusted returns the latest verified ExtendedHeader.
+func (v *Verifier) Trusted() *ExtendedHeader {
+	return v.trusted
+}
+
+// Verify verifies the untrusted ExtendedHeader following the trusted one:
+//   - its DAH must match its DataHash
+//   - an adjacent header must be signed by the trusted next validators
+//   - a non-adjacent header must be within the TrustingPeriod of the trusted one
+//     and signed by enough of the trusted validators
+//
+// The untrusted header becomes trusted once verified.
+func (v *Verifier) Verify(untrst *ExtendedHeader) error {
+	err := v.verify(untrst)
+	if err != nil {
+		return &VerifierError{
+			Height: untrst.Height,
+			Hash:   untrst.Hash(),
+			Reason: err,
+		}
+	}
+
+	v.trusted = untrst
+	return nil
+}
+
+func (v *Verifier) verify(untrst *ExtendedHeader) error {
+	err := untrst.ValidateBasic()
+	if err != nil {
+		return err
+	}
+
+	if dahHash := untrst.DAH.Hash(); !bytes.Equal(dahHash, untrst.DataHash) {
+		return fmt.Errorf("expected DAH hash (%X) to match the header data hash (%X)", dahHash, untrst.DataHash)
+	}
+
+	switch {
+	case untrst.Height <= v.trusted.Height:
+		return fmt.Errorf("expected header above the trusted height %d", v.trusted.Height)
+	case untrst.Height == v.trusted.Height+1:
+		return v.trusted.VerifyAdjacent(untrst)
+	default:
+		if expiry := v.trusted.Time.Add(v.trustingPeriod); untrst.Time.After(expiry) {
+			return fmt.Errorf("trusted header at height %d expired at %v, before the header time %v",
+				v.trusted.Height, expiry, untrst.Time)
+		}
+		return v.trusted.VerifyNonAdjacent(untrst)
+	}
+}
+
+// VerifyAll verifies the untrusted ExtendedHeaders from the Source until it is exhausted.
+// It returns the amount of verified headers and the VerifierError of the first header failing verification.
+func (v *Verifier) VerifyAll(ctx context.Context, src Source) (int, error) {
+	var verified int
+	for {
+		h, err := src.Next(ctx)
+		if err == io.EOF {
+			return verified, nil
+		}
+		if err != nil {
+			return verified, err
+		}
+
+		err = v.Verify(h)
+		if err != nil {
+			return verified, err
+		}
+		verified++
+	}
+}
+
+// FindTrusted reads the ExtendedHeaders from the Source until the one with the given trusted hash.
+func FindTrusted(ctx context.Context, src Source, hash tmbytes.HexBytes) (*ExtendedHeader, error) {
+	for {
+		h, err := src.Next(ctx)
+		if err == io.EOF {
+			return nil, fmt.Errorf("header: trusted header %s is not found in the source", hash)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(h.Hash(), hash) {
+			return h, nil
+		}
+	}
+}
+
+// VerifierError is returned by the Verifier for the first header failing verification.
+type VerifierError struct {
+	Height int64
+	Hash   tmbytes.HexBytes
+	Reason error
+}
+
+func (ve *VerifierError) Error() string {
+	return fmt.Sprintf("header: verifier: header %s at height %d: %s", ve.Hash, ve.Height, ve.Reason)
+}
+
+// Unwrap returns the reason verification failed.
+func (ve *VerifierError) Unwrap() error {
+	return ve.Reason
+}
+
+// rangeSource streams the ExtendedHeaders of a height range from a Getter.
+type rangeSource struct {
+	getter   Getter
+	from, to uint64
+	batch    uint64
+
+	pending []*ExtendedHeader
+}
+
+// NewRangeSource creates a Source streaming the ExtendedHeaders of the given range [from:to]
+// from the Getter, e.g. a Store or an Exchange, requesting them in batches of the given size.
+func NewRangeSource(getter Getter, from, to, batch uint64) Source {
+	if batch == 0 {
+		batch = 1
+	}
+	return &rangeSource{
+		getter: getter,
+		from:   from,
+		to:     to,
+		batch:  batch,
+	}
+}
+
+func (rs *rangeSource) Next(ctx context.Context) (*ExtendedHeader, error) {
+	if len(rs.pending) == 0 {
+		if rs.from > rs.to {
+			return nil, io.EOF
+		}
+
+		end := rs.from + rs.batch
+		if end > rs.to+1 {
+			end = rs.to + 1
+		}
+
+		headers, err := rs.getter.GetRangeByHeight(ctx, rs.from, end)
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(headers)) != end-rs.from {
+			return nil, fmt.Errorf("header: requested %d headers, got %d", end-rs.from, len(headers))
+		}
+		rs.pending, rs.from = headers, end
+	}
+
+	h := rs.pending[0]
+	rs.pending = rs.pending[1:]
+	return h, nil
+}
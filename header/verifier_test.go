@@ -0,0 +1,97 @@
+package header
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/celestia-app/pkg/da"
+
+	"github.com/celestiaorg/celestia-node/share"
+)
+
+func TestVerifier(t *testing.T) {
+	ctx := context.Background()
+	suite := NewTestSuite(t, 3)
+	trusted := suite.Head()
+	headers := suite.GenExtendedHeaders(10)
+
+	t.Run("Adjacent", func(t *testing.T) {
+		v := NewVerifier(trusted)
+		n, err := v.VerifyAll(ctx, &sliceSource{headers: headers})
+		require.NoError(t, err)
+		assert.Equal(t, len(headers), n)
+		assert.Equal(t, headers[len(headers)-1], v.Trusted())
+	})
+
+	t.Run("NonAdjacent", func(t *testing.T) {
+		v := NewVerifier(trusted)
+		n, err := v.VerifyAll(ctx, &sliceSource{headers: []*ExtendedHeader{headers[2], headers[5], headers[9]}})
+		require.NoError(t, err)
+		assert.Equal(t, 3, n)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		v := NewVerifier(trusted, WithTrustingPeriod(time.Nanosecond))
+		err := v.Verify(headers[0])
+		require.NoError(t, err)
+
+		err = v.Verify(headers[5])
+		var verErr *VerifierError
+		require.ErrorAs(t, err, &verErr)
+		assert.Equal(t, headers[5].Height, verErr.Height)
+		assert.Equal(t, headers[0], v.Trusted())
+	})
+
+	t.Run("NotAscending", func(t *testing.T) {
+		v := NewVerifier(headers[5])
+		assert.Error(t, v.Verify(headers[5]))
+		assert.Error(t, v.Verify(headers[2]))
+	})
+
+	t.Run("DAHMismatch", func(t *testing.T) {
+		tampered := *headers[3]
+		dah := da.NewDataAvailabilityHeader(share.RandEDS(t, 2))
+		tampered.DAH = &dah
+
+		v := NewVerifier(trusted)
+		src := &sliceSource{headers: []*ExtendedHeader{headers[0], headers[1], headers[2], &tampered, headers[4]}}
+		n, err := v.VerifyAll(ctx, src)
+		assert.Equal(t, 3, n)
+		var verErr *VerifierError
+		require.ErrorAs(t, err, &verErr)
+		assert.Equal(t, tampered.Height, verErr.Height)
+		assert.Equal(t, headers[2], v.Trusted())
+	})
+
+	t.Run("SourceError", func(t *testing.T) {
+		srcErr := errors.New("source error")
+		v := NewVerifier(trusted)
+		_, err := v.VerifyAll(ctx, &sliceSource{headers: headers[:2], err: srcErr})
+		assert.ErrorIs(t, err, srcErr)
+	})
+}
+
+// sliceSource streams the headers from the slice, failing with the error if set.
+type sliceSource struct {
+	headers []*ExtendedHeader
+	err     error
+}
+
+func (ss *sliceSource) Next(context.Context) (*ExtendedHeader, error) {
+	if len(ss.headers) == 0 {
+		if ss.err != nil {
+			return nil, ss.err
+		}
+		return nil, io.EOF
+	}
+
+	h := ss.headers[0]
+	ss.headers = ss.headers[1:]
+	return h, nil
+}
@@ -8,10 +8,8 @@ import (
 	"github.com/tendermint/tendermint/light"
 )
 
-// TODO(@Wondertan): We should request TrustingPeriod from the network's state params or
-//  listen for network params changes to always have a topical value.
-
 // TrustingPeriod is period through which we can trust a header's validators set.
+// It is the local default used until the trusting period of the network is known.
 //
 // Should be significantly less than the unbonding period (e.g. unbonding
 // period = 3 weeks, trusting period = 2 weeks).
@@ -21,9 +19,9 @@ import (
 // period.
 var TrustingPeriod = 168 * time.Hour
 
-// IsExpired checks if header is expired against trusting period.
-func (eh *ExtendedHeader) IsExpired() bool {
-	expirationTime := eh.Time.Add(TrustingPeriod)
+// IsExpired checks if header is expired against the given trusting period.
+func (eh *ExtendedHeader) IsExpired(trustingPeriod time.Duration) bool {
+	expirationTime := eh.Time.Add(trustingPeriod)
 	return !expirationTime.After(time.Now())
 }
 
@@ -73,6 +71,51 @@ func (eh *ExtendedHeader) VerifyAdjacent(untrst *ExtendedHeader) error {
 	return nil
 }
 
+// VerifyPrevious validates untrusted header preceding the trusted 'eh' by the hash chain.
+// Unlike other verifications, it does not rely on the validator set and only checks that
+// 'eh' commits to the untrusted header through its LastBlockID.
+func (eh *ExtendedHeader) VerifyPrevious(untrst *ExtendedHeader) error {
+	if untrst.Height != eh.Height-1 {
+		return &ErrNonAdjacent{
+			Head:      eh.Height,
+			Attempted: untrst.Height,
+		}
+	}
+
+	if untrst.ChainID != eh.ChainID {
+		return &VerifyError{
+			fmt.Errorf("previous untrusted header has different chain %s, not %s", untrst.ChainID, eh.ChainID),
+		}
+	}
+
+	// recompute the hash, as Hash() is taken from the untrusted Commit
+	if hash := untrst.RawHeader.Hash(); !bytes.Equal(hash, eh.LastHeader()) {
+		return &VerifyError{
+			fmt.Errorf("expected last block hash (%X) of trusted header to match the hash of untrusted header (%X)",
+				eh.LastHeader(),
+				hash,
+			),
+		}
+	}
+
+	if !bytes.Equal(untrst.Hash(), eh.LastHeader()) {
+		return &VerifyError{
+			fmt.Errorf("expected untrusted header commit for block %X, got %X", eh.LastHeader(), untrst.Hash()),
+		}
+	}
+
+	if !untrst.Time.Before(eh.Time) {
+		return &VerifyError{
+			fmt.Errorf("expected previous untrusted header time %v to be before trusted header time %v",
+				untrst.Time,
+				eh.Time,
+			),
+		}
+	}
+
+	return nil
+}
+
 // clockDrift defines how much new header's time can drift into
 // the future relative to the now time during verification.
 var clockDrift = 10 * time.Second
@@ -104,3 +147,8 @@ type VerifyError struct {
 func (vr *VerifyError) Error() string {
 	return fmt.Sprintf("header: verify: %s", vr.Reason.Error())
 }
+
+// Unwrap returns the reason verification failed.
+func (vr *VerifyError) Unwrap() error {
+	return vr.Reason
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	tmrand "github.com/tendermint/tendermint/libs/rand"
 )
@@ -65,3 +66,72 @@ func TestVerifyAdjacent(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyPrevious(t *testing.T) {
+	h := NewTestSuite(t, 2).GenExtendedHeaders(3)
+	// the genesis header is committed to as well
+	assert.NoError(t, h[1].VerifyPrevious(h[0]))
+	// only the adjacent header can be verified
+	var nonAdj *ErrNonAdjacent
+	assert.ErrorAs(t, h[2].VerifyPrevious(h[0]), &nonAdj)
+
+	tests := []struct {
+		name string
+		// prepare modifies the untrusted header before the trusted one commits to it
+		prepare func(untrusted *RawHeader)
+		// tamper modifies the untrusted header after the trusted one committed to it
+		tamper func(untrusted *ExtendedHeader)
+		err    string
+	}{
+		{
+			name: "Valid",
+		},
+		{
+			name: "Time",
+			prepare: func(untrusted *RawHeader) {
+				untrusted.Time = untrusted.Time.Add(time.Hour)
+			},
+			err: "to be before trusted header time",
+		},
+		{
+			name: "ChainID",
+			prepare: func(untrusted *RawHeader) {
+				untrusted.ChainID = "toaster"
+			},
+			err: "different chain",
+		},
+		{
+			name: "Hash",
+			tamper: func(untrusted *ExtendedHeader) {
+				untrusted.DataHash = tmrand.Bytes(32)
+			},
+			err: "to match the hash of untrusted header",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// every case gets fresh headers, so the previous cases don't affect it
+			suite := NewTestSuite(t, 2)
+			suite.GenExtendedHeader()
+			untrusted := suite.GenExtendedHeader()
+			if test.prepare != nil {
+				test.prepare(&untrusted.RawHeader)
+				untrusted.Commit = suite.Commit(&untrusted.RawHeader)
+			}
+			trusted := suite.GenExtendedHeader()
+			if test.tamper != nil {
+				test.tamper(untrusted)
+			}
+
+			err := trusted.VerifyPrevious(untrusted)
+			if test.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var verErr *VerifyError
+			require.ErrorAs(t, err, &verErr)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
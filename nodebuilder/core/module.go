@@ -31,6 +31,7 @@ func ConstructModule(tp node.Type, cfg *Config, options ...fx.Option) fx.Option
 			baseComponents,
 			fx.Provide(core.NewBlockFetcher),
 			fxutil.ProvideAs(headercore.NewExchange, new(header.Exchange)),
+			fxutil.ProvideAs(headercore.NewParamsGetter, new(header.ParamsGetter)),
 			fx.Invoke(fx.Annotate(
 				headercore.NewListener,
 				fx.OnStart(func(ctx context.Context, listener *headercore.Listener) error {
@@ -0,0 +1,145 @@
+package daser
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/celestiaorg/celestia-node/das"
+	"github.com/celestiaorg/celestia-node/nodebuilder/node"
+)
+
+// Config contains configuration parameters for sampling of the data availability.
+// Zero values are set to the defaults of the node type, e.g. for configs written before the field was added.
+type Config struct {
+	// SamplingRange is the maximum amount of headers sampled by a single worker in one job.
+	SamplingRange uint64
+	// ConcurrencyLimit is the maximum amount of sampling workers running in parallel.
+	ConcurrencyLimit int
+	// BackgroundStoreInterval is the period the sampling checkpoint is backed up at in the background.
+	BackgroundStoreInterval time.Duration
+	// PriorityQueueSize is the maximum amount of jobs of the recent headers sampled before the older ones.
+	PriorityQueueSize int
+	// GenesisHeight is the height sampling starts from, unless there is a checkpoint to resume from.
+	GenesisHeight uint64
+	// SamplingWindow limits sampling to the headers produced within the period before now, e.g. 30 days,
+	// so that a new node does not sample the whole chain. Headers aging out of it are not retried anymore.
+	// Unlike the other fields, zero disables the limit.
+	SamplingWindow time.Duration
+	// SamplingWindowHeights limits sampling to the amount of the most recent heights.
+	// If both window limits are set, headers are sampled while they are within either of them.
+	// Unlike the other fields, zero disables the limit.
+	SamplingWindowHeights uint64
+	// RetryBackoff is the delay before the first retry of a failed header, doubled with every next attempt.
+	RetryBackoff time.Duration
+	// RetryMaxBackoff is the upper limit of the delay between the retries.
+	RetryMaxBackoff time.Duration
+	// RetryJitter is the fraction of the delay the retries are randomly spread by, within [0:1].
+	// Unlike the other retry fields, zero disables the jitter.
+	RetryJitter float64
+	// MaxAttempts is the amount of failed sampling attempts after which a header is considered
+	// permanently unavailable and is not retried anymore.
+	MaxAttempts int
+}
+
+// DefaultConfig returns the default Config for the given node type.
+// Full nodes retrieve whole blocks instead of sampling them, so fewer of them are processed at once,
+// and they do it for the whole chain, while light nodes only sample the last 30 days.
+// Bridge nodes don't sample, so the light node defaults are used for them.
+func DefaultConfig(tp node.Type) Config {
+	params := das.DefaultParameters()
+	cfg := Config{
+		SamplingRange:           params.SamplingRange,
+		ConcurrencyLimit:        params.ConcurrencyLimit,
+		BackgroundStoreInterval: params.BackgroundStoreInterval,
+		PriorityQueueSize:       params.PriorityQueueSize,
+		GenesisHeight:           params.GenesisHeight,
+		SamplingWindow:          time.Hour * 24 * 30,
+		SamplingWindowHeights:   0,
+		RetryBackoff:            params.RetryBackoff,
+		RetryMaxBackoff:         params.RetryMaxBackoff,
+		RetryJitter:             params.RetryJitter,
+		MaxAttempts:             params.MaxAttempts,
+	}
+	if tp == node.Full {
+		cfg.SamplingRange = 50
+		cfg.ConcurrencyLimit = 6
+		cfg.PriorityQueueSize = cfg.ConcurrencyLimit * 4
+		cfg.SamplingWindow = 0
+	}
+	return cfg
+}
+
+// Validate performs basic validation of the config.
+func (cfg *Config) Validate() error {
+	if cfg.ConcurrencyLimit < 0 {
+		return fmt.Errorf("nodebuilder/daser: concurrency limit must not be negative")
+	}
+	if cfg.BackgroundStoreInterval < 0 {
+		return fmt.Errorf("nodebuilder/daser: background store interval must not be negative")
+	}
+	if cfg.PriorityQueueSize < 0 {
+		return fmt.Errorf("nodebuilder/daser: priority queue size must not be negative")
+	}
+	if cfg.SamplingWindow < 0 {
+		return fmt.Errorf("nodebuilder/daser: sampling window must not be negative")
+	}
+	if cfg.RetryBackoff < 0 || cfg.RetryMaxBackoff < 0 {
+		return fmt.Errorf("nodebuilder/daser: retry backoff must not be negative")
+	}
+	if cfg.RetryBackoff > 0 && cfg.RetryMaxBackoff > 0 && cfg.RetryMaxBackoff < cfg.RetryBackoff {
+		return fmt.Errorf("nodebuilder/daser: retry max backoff must not be less than retry backoff")
+	}
+	if cfg.RetryJitter < 0 || cfg.RetryJitter > 1 {
+		return fmt.Errorf("nodebuilder/daser: retry jitter must be within [0:1]")
+	}
+	if cfg.MaxAttempts < 0 {
+		return fmt.Errorf("nodebuilder/daser: max attempts must not be negative")
+	}
+	return nil
+}
+
+// setDefaults sets the zero values of the config to the defaults of the given node type,
+// except for the sampling window and the retry jitter, zero values of which disable them.
+func (cfg *Config) setDefaults(tp node.Type) {
+	def := DefaultConfig(tp)
+	if cfg.SamplingRange == 0 {
+		cfg.SamplingRange = def.SamplingRange
+	}
+	if cfg.ConcurrencyLimit == 0 {
+		cfg.ConcurrencyLimit = def.ConcurrencyLimit
+	}
+	if cfg.BackgroundStoreInterval == 0 {
+		cfg.BackgroundStoreInterval = def.BackgroundStoreInterval
+	}
+	if cfg.PriorityQueueSize == 0 {
+		cfg.PriorityQueueSize = def.PriorityQueueSize
+	}
+	if cfg.GenesisHeight == 0 {
+		cfg.GenesisHeight = def.GenesisHeight
+	}
+	if cfg.RetryBackoff == 0 {
+		cfg.RetryBackoff = def.RetryBackoff
+	}
+	if cfg.RetryMaxBackoff == 0 {
+		cfg.RetryMaxBackoff = def.RetryMaxBackoff
+	}
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = def.MaxAttempts
+	}
+}
+
+// options converts the config into the DASer options.
+func (cfg *Config) options() []das.Option {
+	return []das.Option{
+		das.WithSamplingRange(cfg.SamplingRange),
+		das.WithConcurrencyLimit(cfg.ConcurrencyLimit),
+		das.WithBackgroundStoreInterval(cfg.BackgroundStoreInterval),
+		das.WithPriorityQueueSize(cfg.PriorityQueueSize),
+		das.WithGenesisHeight(cfg.GenesisHeight),
+		das.WithSamplingWindow(cfg.SamplingWindow),
+		das.WithSamplingWindowHeights(cfg.SamplingWindowHeights),
+		das.WithRetryBackoff(cfg.RetryBackoff, cfg.RetryMaxBackoff),
+		das.WithRetryJitter(cfg.RetryJitter),
+		das.WithMaxAttempts(cfg.MaxAttempts),
+	}
+}
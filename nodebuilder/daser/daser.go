@@ -10,11 +10,12 @@ import (
 )
 
 func NewDASer(
+	cfg Config,
 	da share.Availability,
 	hsub header.Subscriber,
 	store header.Store,
 	batching datastore.Batching,
 	fraudService fraud.Module,
-) *das.DASer {
-	return das.NewDASer(da, hsub, store, batching, fraudService)
+) (*das.DASer, error) {
+	return das.NewDASer(da, hsub, store, batching, fraudService, cfg.options()...)
 }
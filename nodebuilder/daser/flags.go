@@ -0,0 +1,157 @@
+package daser
+
+import (
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+)
+
+var (
+	samplingRangeFlag           = "daser.sampling-range"
+	concurrencyLimitFlag        = "daser.concurrency-limit"
+	backgroundStoreIntervalFlag = "daser.background-store-interval"
+	priorityQueueSizeFlag       = "daser.priority-queue-size"
+	genesisHeightFlag           = "daser.genesis-height"
+	samplingWindowFlag          = "daser.sampling-window"
+	samplingWindowHeightsFlag   = "daser.sampling-window-heights"
+	retryBackoffFlag            = "daser.retry-backoff"
+	retryMaxBackoffFlag         = "daser.retry-max-backoff"
+	retryJitterFlag             = "daser.retry-jitter"
+	maxAttemptsFlag             = "daser.max-attempts"
+)
+
+// Flags gives a set of DASer flags.
+func Flags() *flag.FlagSet {
+	flags := &flag.FlagSet{}
+
+	flags.Uint64(
+		samplingRangeFlag,
+		0,
+		"Maximum amount of headers sampled by a single worker in one job.",
+	)
+	flags.Int(
+		concurrencyLimitFlag,
+		0,
+		"Maximum amount of sampling workers running in parallel.",
+	)
+	flags.Duration(
+		backgroundStoreIntervalFlag,
+		0,
+		"Period the sampling checkpoint is backed up at in the background, e.g. '10m'.",
+	)
+	flags.Int(
+		priorityQueueSizeFlag,
+		0,
+		"Maximum amount of jobs of the recent headers sampled before the older ones.",
+	)
+	flags.Uint64(
+		genesisHeightFlag,
+		0,
+		"Height sampling starts from, unless there is a checkpoint to resume from.",
+	)
+	flags.Duration(
+		samplingWindowFlag,
+		0,
+		"Period before now headers are sampled within, e.g. '720h'. Zero samples the whole chain.",
+	)
+	flags.Uint64(
+		samplingWindowHeightsFlag,
+		0,
+		"Amount of the most recent heights sampled. Zero samples the whole chain.",
+	)
+	flags.Duration(
+		retryBackoffFlag,
+		0,
+		"Delay before the first retry of a failed header, doubled with every next attempt, e.g. '1m'.",
+	)
+	flags.Duration(
+		retryMaxBackoffFlag,
+		0,
+		"Upper limit of the delay between the retries of a failed header, e.g. '1h'.",
+	)
+	flags.Float64(
+		retryJitterFlag,
+		0,
+		"Fraction of the retry delay the retries are randomly spread by, within [0:1]. Zero disables the jitter.",
+	)
+	flags.Int(
+		maxAttemptsFlag,
+		0,
+		"Amount of failed sampling attempts after which a header is considered permanently unavailable.",
+	)
+
+	return flags
+}
+
+// ParseFlags parses DASer flags from the given cmd and applies them to the passed config.
+// Only the flags set explicitly override the config.
+func ParseFlags(cmd *cobra.Command, cfg *Config) error {
+	flags := cmd.Flags()
+	var err error
+	if flags.Changed(samplingRangeFlag) {
+		cfg.SamplingRange, err = flags.GetUint64(samplingRangeFlag)
+		if err != nil {
+			return err
+		}
+	}
+	if flags.Changed(concurrencyLimitFlag) {
+		cfg.ConcurrencyLimit, err = flags.GetInt(concurrencyLimitFlag)
+		if err != nil {
+			return err
+		}
+	}
+	if flags.Changed(backgroundStoreIntervalFlag) {
+		cfg.BackgroundStoreInterval, err = flags.GetDuration(backgroundStoreIntervalFlag)
+		if err != nil {
+			return err
+		}
+	}
+	if flags.Changed(priorityQueueSizeFlag) {
+		cfg.PriorityQueueSize, err = flags.GetInt(priorityQueueSizeFlag)
+		if err != nil {
+			return err
+		}
+	}
+	if flags.Changed(genesisHeightFlag) {
+		cfg.GenesisHeight, err = flags.GetUint64(genesisHeightFlag)
+		if err != nil {
+			return err
+		}
+	}
+	if flags.Changed(samplingWindowFlag) {
+		cfg.SamplingWindow, err = flags.GetDuration(samplingWindowFlag)
+		if err != nil {
+			return err
+		}
+	}
+	if flags.Changed(samplingWindowHeightsFlag) {
+		cfg.SamplingWindowHeights, err = flags.GetUint64(samplingWindowHeightsFlag)
+		if err != nil {
+			return err
+		}
+	}
+	if flags.Changed(retryBackoffFlag) {
+		cfg.RetryBackoff, err = flags.GetDuration(retryBackoffFlag)
+		if err != nil {
+			return err
+		}
+	}
+	if flags.Changed(retryMaxBackoffFlag) {
+		cfg.RetryMaxBackoff, err = flags.GetDuration(retryMaxBackoffFlag)
+		if err != nil {
+			return err
+		}
+	}
+	if flags.Changed(retryJitterFlag) {
+		cfg.RetryJitter, err = flags.GetFloat64(retryJitterFlag)
+		if err != nil {
+			return err
+		}
+	}
+	if flags.Changed(maxAttemptsFlag) {
+		cfg.MaxAttempts, err = flags.GetInt(maxAttemptsFlag)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -11,11 +11,17 @@ import (
 	"github.com/celestiaorg/celestia-node/nodebuilder/node"
 )
 
-func ConstructModule(tp node.Type) fx.Option {
+func ConstructModule(tp node.Type, cfg *Config) fx.Option {
+	// sanitize config values before constructing module
+	cfg.setDefaults(tp)
+	cfgErr := cfg.Validate()
+
 	switch tp {
 	case node.Light, node.Full:
 		return fx.Module(
 			"daser",
+			fx.Supply(*cfg),
+			fx.Error(cfgErr),
 			fx.Provide(fx.Annotate(
 				NewDASer,
 				fx.OnStart(func(startCtx, ctx context.Context, fservice fraudServ.Module, das *das.DASer) error {
@@ -2,11 +2,15 @@ package header
 
 import (
 	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
 
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/multiformats/go-multiaddr"
 	tmbytes "github.com/tendermint/tendermint/libs/bytes"
 
+	"github.com/celestiaorg/celestia-node/header/p2p"
 	"github.com/celestiaorg/celestia-node/params"
 )
 
@@ -19,12 +23,56 @@ type Config struct {
 	// Note: The trusted does *not* imply Headers are not verified, but trusted as reliable to fetch headers
 	// at any moment.
 	TrustedPeers []string
+	// RetentionHeights is the amount of the most recent headers kept in the header store.
+	// Older headers are pruned in the background. Zero keeps all the headers.
+	RetentionHeights uint64
+	// RetentionPeriod is the time window relative to the head's timestamp
+	// headers are kept within in the header store. Zero keeps all the headers.
+	// NOTE: If both retention values are set, headers are pruned only once they fall out of both.
+	RetentionPeriod time.Duration
+	// BackwardSync enables syncing of headers preceding the TrustedHash down to the genesis.
+	// Allows to initialize from a recent trusted header and still fill in the history.
+	BackwardSync bool
+	// BroadcastConflicts enables broadcasting of ConflictingHeaders fraud proofs to the network
+	// once conflicting headers are detected. Nodes receiving a proof halt syncing of headers.
+	BroadcastConflicts bool
+	// Bisection enables skipping verification of network heads too far ahead of the local head
+	// to be verified directly, e.g. after a long downtime. Such a head is verified through a minimal
+	// set of pivot headers, while the headers in between are synced later on.
+	Bisection bool
+	// ServerRateLimits limits the requests for headers served to other peers, so that a single peer
+	// can't starve the node. The zero value of any limit disables it, and all of them are disabled by default.
+	ServerRateLimits p2p.RateLimits
+	// InitQuorum enables strict subjective initialization, which happens once the local head expires.
+	// The network head is only accepted if at least the given amount of trusted peers agree on it,
+	// otherwise the node refuses to proceed. Zero accepts the best head reported by the trusted peers.
+	InitQuorum int
+	// InitTrustedHash is an optional hash of a header the network head must be verifiable from
+	// during subjective initialization.
+	InitTrustedHash string
+	// InitTrustedHeight is an optional height the network head must not be below
+	// during subjective initialization.
+	InitTrustedHeight uint64
+	// ExchangeRPC is an optional URL of the RPC of a trusted node, e.g. 'https://node.example.com:26658'.
+	// If set, headers are requested from it over HTTP instead of from the peers over libp2p,
+	// which is useful where libp2p connections can't be made. The headers are verified as usual.
+	ExchangeRPC string
 }
 
 func DefaultConfig() Config {
 	return Config{
-		TrustedHash:  "",
-		TrustedPeers: make([]string, 0),
+		TrustedHash:        "",
+		TrustedPeers:       make([]string, 0),
+		RetentionHeights:   0,
+		RetentionPeriod:    0,
+		BackwardSync:       false,
+		BroadcastConflicts: false,
+		Bisection:          false,
+		ServerRateLimits:   p2p.RateLimits{},
+		InitQuorum:         0,
+		InitTrustedHash:    "",
+		InitTrustedHeight:  0,
+		ExchangeRPC:        "",
 	}
 }
 
@@ -62,5 +110,36 @@ func (cfg *Config) trustedHash(net params.Network) (tmbytes.HexBytes, error) {
 
 // Validate performs basic validation of the config.
 func (cfg *Config) Validate() error {
+	if cfg.RetentionPeriod < 0 {
+		return fmt.Errorf("nodebuilder/header: retention period must not be negative")
+	}
+	if cfg.BackwardSync && (cfg.RetentionHeights > 0 || cfg.RetentionPeriod > 0) {
+		return fmt.Errorf("nodebuilder/header: backward sync can't be used together with retention")
+	}
+	if err := cfg.ServerRateLimits.Validate(); err != nil {
+		return err
+	}
+	if cfg.InitQuorum < 0 {
+		return fmt.Errorf("nodebuilder/header: init quorum must not be negative")
+	}
+	if len(cfg.TrustedPeers) != 0 && cfg.InitQuorum > len(cfg.TrustedPeers) {
+		return fmt.Errorf("nodebuilder/header: init quorum %d exceeds the amount of trusted peers %d",
+			cfg.InitQuorum, len(cfg.TrustedPeers))
+	}
+	if _, err := hex.DecodeString(cfg.InitTrustedHash); err != nil {
+		return fmt.Errorf("nodebuilder/header: invalid init trusted hash: %w", err)
+	}
+	if cfg.ExchangeRPC != "" && cfg.InitQuorum > 0 {
+		return fmt.Errorf("nodebuilder/header: init quorum can't be used together with exchange RPC")
+	}
+	if cfg.ExchangeRPC != "" {
+		u, err := url.Parse(cfg.ExchangeRPC)
+		if err != nil {
+			return fmt.Errorf("nodebuilder/header: invalid exchange RPC url: %w", err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("nodebuilder/header: exchange RPC url must be an absolute http(s) url: %s", cfg.ExchangeRPC)
+		}
+	}
 	return nil
 }
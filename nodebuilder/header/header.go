@@ -2,23 +2,28 @@ package header
 
 import (
 	"context"
-	"time"
+	"encoding/hex"
 
+	"github.com/ipfs/go-datastore"
 	"github.com/libp2p/go-libp2p-core/host"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/peerstore"
 	"go.uber.org/fx"
 
+	"github.com/celestiaorg/celestia-node/fraud"
 	"github.com/celestiaorg/celestia-node/header"
+	"github.com/celestiaorg/celestia-node/header/netparams"
 	"github.com/celestiaorg/celestia-node/header/p2p"
+	"github.com/celestiaorg/celestia-node/header/rpc"
 	"github.com/celestiaorg/celestia-node/header/store"
 	"github.com/celestiaorg/celestia-node/header/sync"
+	fraudServ "github.com/celestiaorg/celestia-node/nodebuilder/fraud"
 	"github.com/celestiaorg/celestia-node/params"
 )
 
 // newP2PExchange constructs new Exchange for headers.
-func newP2PExchange(cfg Config) func(params.Bootstrappers, host.Host) (header.Exchange, error) {
-	return func(bpeers params.Bootstrappers, host host.Host) (header.Exchange, error) {
+func newP2PExchange(cfg Config) func(fx.Lifecycle, params.Bootstrappers, host.Host) (*p2p.Exchange, error) {
+	return func(lc fx.Lifecycle, bpeers params.Bootstrappers, host host.Host) (*p2p.Exchange, error) {
 		peers, err := cfg.trustedPeers(bpeers)
 		if err != nil {
 			return nil, err
@@ -28,13 +33,107 @@ func newP2PExchange(cfg Config) func(params.Bootstrappers, host.Host) (header.Ex
 			ids[index] = peer.ID
 			host.Peerstore().AddAddrs(peer.ID, peer.Addrs, peerstore.PermanentAddrTTL)
 		}
-		return p2p.NewExchange(host, ids), nil
+		exchange := p2p.NewExchange(host, ids)
+		lc.Append(fx.Hook{
+			OnStart: exchange.Start,
+			OnStop:  exchange.Stop,
+		})
+		return exchange, nil
+	}
+}
+
+// newExchange chooses the Exchange headers are requested with: the RPC of the trusted node, if configured,
+// or the peers otherwise.
+func newExchange(cfg Config) func(*p2p.Exchange) header.Exchange {
+	return func(ex *p2p.Exchange) header.Exchange {
+		if cfg.ExchangeRPC != "" {
+			return rpc.NewExchange(cfg.ExchangeRPC)
+		}
+		return ex
+	}
+}
+
+// newParamsGetter chooses the ParamsGetter the network parameters are requested with:
+// the Exchange headers are requested with, if it serves them, or the peers otherwise.
+func newParamsGetter(ex header.Exchange, p2pEx *p2p.Exchange) header.ParamsGetter {
+	if getter, ok := ex.(header.ParamsGetter); ok {
+		return getter
+	}
+	return p2pEx
+}
+
+// newExchangeServer constructs new ExchangeServer limiting inbound requests according to the config.
+// It also serves the known network parameters to the peers.
+func newExchangeServer(cfg Config) func(host.Host, header.Store, *netparams.Service) *p2p.ExchangeServer {
+	return func(host host.Host, store header.Store, netParams *netparams.Service) *p2p.ExchangeServer {
+		return p2p.NewExchangeServer(host, store,
+			p2p.WithRateLimits(cfg.ServerRateLimits),
+			p2p.WithParamsGetter(netParams),
+		)
+	}
+}
+
+// newNetParams constructs new Service keeping the network parameters got with the ParamsGetter.
+func newNetParams(getter header.ParamsGetter, ds datastore.Batching) *netparams.Service {
+	return netparams.NewService(getter, ds)
+}
+
+// newStore constructs new Store for headers pruning them according to the configured retention.
+func newStore(cfg Config) func(datastore.Batching) (header.Store, error) {
+	return func(ds datastore.Batching) (header.Store, error) {
+		return store.NewStoreWithRetention(ds, store.RetentionPolicy{
+			Heights: cfg.RetentionHeights,
+			Period:  cfg.RetentionPeriod,
+		})
 	}
 }
 
 // newSyncer constructs new Syncer for headers.
-func newSyncer(ex header.Exchange, store initStore, sub header.Subscriber, duration time.Duration) *sync.Syncer {
-	return sync.NewSyncer(ex, store, sub, duration)
+func newSyncer(cfg Config) func(
+	header.Exchange,
+	initStore,
+	header.Subscriber,
+	*netparams.Service,
+	datastore.Batching,
+	fraudServ.Module,
+) *sync.Syncer {
+	return func(
+		ex header.Exchange,
+		store initStore,
+		sub header.Subscriber,
+		netParams *netparams.Service,
+		ds datastore.Batching,
+		fservice fraudServ.Module,
+	) *sync.Syncer {
+		opts := []sync.Option{
+			sync.WithDatastore(ds),
+			sync.WithBlockTime(netParams.BlockTime),
+			sync.WithTrustingPeriod(netParams.TrustingPeriod),
+		}
+		if cfg.InitTrustedHash != "" || cfg.InitTrustedHeight != 0 {
+			// the hash is ensured to be valid by Config.Validate
+			hash, _ := hex.DecodeString(cfg.InitTrustedHash)
+			opts = append(opts, sync.WithTrustedCheckpoint(hash, cfg.InitTrustedHeight))
+		}
+		if cfg.InitQuorum > 0 {
+			opts = append(opts, sync.WithInitQuorum(cfg.InitQuorum))
+		}
+		if cfg.BackwardSync {
+			opts = append(opts, sync.WithBackwardSync())
+		}
+		if cfg.Bisection {
+			opts = append(opts, sync.WithBisection())
+		}
+		if cfg.BroadcastConflicts {
+			opts = append(opts, sync.WithConflictHandler(func(ctx context.Context, c *sync.Conflict) {
+				proof := fraud.CreateConflictingHeadersProof(c.Known, c.Conflicting)
+				if err := fservice.Broadcast(ctx, proof); err != nil {
+					log.Errorw("broadcasting conflicting headers proof", "height", c.Height(), "err", err)
+				}
+			}))
+		}
+		return sync.NewSyncer(ex, store, sub, netParams.BlockTime(), opts...)
+	}
 }
 
 // initStore is a type representing initialized header store.
@@ -8,12 +8,11 @@ import (
 
 	"github.com/celestiaorg/celestia-node/fraud"
 	"github.com/celestiaorg/celestia-node/header"
+	"github.com/celestiaorg/celestia-node/header/netparams"
 	"github.com/celestiaorg/celestia-node/header/p2p"
-	"github.com/celestiaorg/celestia-node/header/store"
 	"github.com/celestiaorg/celestia-node/header/sync"
 	fraudServ "github.com/celestiaorg/celestia-node/nodebuilder/fraud"
 	"github.com/celestiaorg/celestia-node/nodebuilder/node"
-	"github.com/celestiaorg/celestia-node/params"
 )
 
 var log = logging.Logger("header-module")
@@ -25,10 +24,18 @@ func ConstructModule(tp node.Type, cfg *Config) fx.Option {
 	baseComponents := fx.Options(
 		fx.Supply(*cfg),
 		fx.Error(cfgErr),
-		fx.Supply(params.BlockTime),
 		fx.Provide(NewHeaderService),
 		fx.Provide(fx.Annotate(
-			store.NewStore,
+			newNetParams,
+			fx.OnStart(func(ctx context.Context, netParams *netparams.Service) error {
+				return netParams.Start(ctx)
+			}),
+			fx.OnStop(func(ctx context.Context, netParams *netparams.Service) error {
+				return netParams.Stop(ctx)
+			}),
+		)),
+		fx.Provide(fx.Annotate(
+			newStore(*cfg),
 			fx.OnStart(func(ctx context.Context, store header.Store) error {
 				return store.Start(ctx)
 			}),
@@ -41,7 +48,7 @@ func ConstructModule(tp node.Type, cfg *Config) fx.Option {
 			return subscriber
 		}),
 		fx.Provide(fx.Annotate(
-			newSyncer,
+			newSyncer(*cfg),
 			fx.OnStart(func(startCtx, ctx context.Context, fservice fraudServ.Module, syncer *sync.Syncer) error {
 				syncerStartFunc := func(ctx context.Context) error {
 					err := syncer.Start(ctx)
@@ -50,12 +57,19 @@ func ConstructModule(tp node.Type, cfg *Config) fx.Option {
 						return err
 					case header.ErrNoHead:
 						log.Warnw("Syncer running on uninitialized Store - headers won't be synced")
+					case sync.ErrConflict:
+						log.Errorw("Syncer is halted due to conflicting headers - headers won't be synced",
+							"conflicts", len(syncer.Conflicts()))
 					case nil:
 					}
 					return nil
 				}
-				return fraudServ.Lifecycle(startCtx, ctx, fraud.BadEncoding, fservice,
-					syncerStartFunc, syncer.Stop)
+				// the Syncer is halted by the proofs of both the bad encoding and the conflicting headers
+				return fraudServ.Lifecycle(startCtx, ctx, fraud.ConflictingHeaders, fservice,
+					func(startCtx context.Context) error {
+						return fraudServ.Lifecycle(startCtx, ctx, fraud.BadEncoding, fservice,
+							syncerStartFunc, syncer.Stop)
+					}, syncer.Stop)
 			}),
 			fx.OnStop(func(ctx context.Context, syncer *sync.Syncer) error {
 				return syncer.Stop(ctx)
@@ -71,7 +85,7 @@ func ConstructModule(tp node.Type, cfg *Config) fx.Option {
 			}),
 		)),
 		fx.Provide(fx.Annotate(
-			p2p.NewExchangeServer,
+			newExchangeServer(*cfg),
 			fx.OnStart(func(ctx context.Context, server *p2p.ExchangeServer) error {
 				return server.Start(ctx)
 			}),
@@ -87,6 +101,10 @@ func ConstructModule(tp node.Type, cfg *Config) fx.Option {
 			"header",
 			baseComponents,
 			fx.Provide(newP2PExchange(*cfg)),
+			fx.Provide(newExchange(*cfg)),
+			// peers without access to Core get the network parameters from the trusted node they sync with,
+			// either from its RPC or from the trusted peers
+			fx.Provide(newParamsGetter),
 		)
 	case node.Bridge:
 		return fx.Module(
@@ -101,3 +119,10 @@ func ConstructModule(tp node.Type, cfg *Config) fx.Option {
 		panic("invalid node type")
 	}
 }
+
+// WithMetrics enables metrics of the header module's components.
+func WithMetrics() fx.Option {
+	return fx.Invoke(func(server *p2p.ExchangeServer) error {
+		return server.InitMetrics()
+	})
+}
@@ -2,8 +2,12 @@ package header
 
 import (
 	"context"
+	"time"
+
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
 
 	"github.com/celestiaorg/celestia-node/header"
+	"github.com/celestiaorg/celestia-node/header/netparams"
 	"github.com/celestiaorg/celestia-node/header/p2p"
 	"github.com/celestiaorg/celestia-node/header/sync"
 )
@@ -12,10 +16,32 @@ type Module interface {
 	// GetByHeight returns the ExtendedHeader at the given height, blocking
 	// until header has been processed by the store or context deadline is exceeded.
 	GetByHeight(context.Context, uint64) (*header.ExtendedHeader, error)
+	// GetByHash returns the ExtendedHeader with the given hash.
+	GetByHash(context.Context, tmbytes.HexBytes) (*header.ExtendedHeader, error)
+	// GetRangeByHeight returns the stored ExtendedHeaders in the given range [from:to).
+	GetRangeByHeight(ctx context.Context, from, to uint64) ([]*header.ExtendedHeader, error)
+	// GetByTime returns the ExtendedHeader chosen by the given time according to the TimeLookup:
+	// the one with exactly the given time, the latest one before it, or the earliest one after it.
+	GetByTime(context.Context, time.Time, header.TimeLookup) (*header.ExtendedHeader, error)
+	// GetByDataHash returns the ExtendedHeader committing to the given data root, i.e. DAH hash.
+	GetByDataHash(context.Context, tmbytes.HexBytes) (*header.ExtendedHeader, error)
 	// Head returns the ExtendedHeader of the chain head.
 	Head(context.Context) (*header.ExtendedHeader, error)
 	// IsSyncing returns the status of sync
 	IsSyncing() bool
+	// SyncState returns the state of the current sync, if in progress, or the latest one,
+	// including its rate and the estimated time left until it is finished.
+	SyncState(context.Context) (sync.State, error)
+	// SyncHistory returns the states of the latest finished syncs, oldest first.
+	SyncHistory(context.Context) ([]sync.State, error)
+	// Subscribe subscribes to the new ExtendedHeaders matching all the given Filters,
+	// e.g. header.WithNamespace or header.NonEmpty. The channel is closed once the context is done.
+	Subscribe(context.Context, ...header.Filter) (<-chan *header.ExtendedHeader, error)
+	// Conflicts returns the evidence of conflicting headers detected during sync, if any.
+	// Syncing is halted once a conflict is detected.
+	Conflicts(context.Context) ([]*sync.Conflict, error)
+	// NetworkParams returns the parameters of the network headers are synced and verified with.
+	NetworkParams(context.Context) (*header.NetworkParams, error)
 }
 
 // service represents the header service that can be started / stopped on a node.
@@ -28,6 +54,7 @@ type service struct {
 	sub       header.Subscriber
 	p2pServer *p2p.ExchangeServer
 	store     header.Store
+	netParams *netparams.Service
 }
 
 // NewHeaderService creates a new instance of header service.
@@ -36,13 +63,15 @@ func NewHeaderService(
 	sub header.Subscriber,
 	p2pServer *p2p.ExchangeServer,
 	ex header.Exchange,
-	store header.Store) Module {
+	store header.Store,
+	netParams *netparams.Service) Module {
 	return &service{
 		syncer:    syncer,
 		sub:       sub,
 		p2pServer: p2pServer,
 		ex:        ex,
 		store:     store,
+		netParams: netParams,
 	}
 }
 
@@ -50,6 +79,26 @@ func (s *service) GetByHeight(ctx context.Context, height uint64) (*header.Exten
 	return s.store.GetByHeight(ctx, height)
 }
 
+func (s *service) GetByHash(ctx context.Context, hash tmbytes.HexBytes) (*header.ExtendedHeader, error) {
+	return s.store.Get(ctx, hash)
+}
+
+func (s *service) GetRangeByHeight(ctx context.Context, from, to uint64) ([]*header.ExtendedHeader, error) {
+	return s.store.GetRangeByHeight(ctx, from, to)
+}
+
+func (s *service) GetByTime(
+	ctx context.Context,
+	t time.Time,
+	lookup header.TimeLookup,
+) (*header.ExtendedHeader, error) {
+	return s.store.GetByTime(ctx, t, lookup)
+}
+
+func (s *service) GetByDataHash(ctx context.Context, hash tmbytes.HexBytes) (*header.ExtendedHeader, error) {
+	return s.store.GetByDataHash(ctx, hash)
+}
+
 func (s *service) Head(ctx context.Context) (*header.ExtendedHeader, error) {
 	return s.store.Head(ctx)
 }
@@ -57,3 +106,47 @@ func (s *service) Head(ctx context.Context) (*header.ExtendedHeader, error) {
 func (s *service) IsSyncing() bool {
 	return !s.syncer.State().Finished()
 }
+
+func (s *service) SyncState(context.Context) (sync.State, error) {
+	return s.syncer.State(), nil
+}
+
+func (s *service) SyncHistory(context.Context) ([]sync.State, error) {
+	return s.syncer.History(), nil
+}
+
+func (s *service) Conflicts(context.Context) ([]*sync.Conflict, error) {
+	return s.syncer.Conflicts(), nil
+}
+
+func (s *service) NetworkParams(ctx context.Context) (*header.NetworkParams, error) {
+	return s.netParams.NetworkParams(ctx)
+}
+
+func (s *service) Subscribe(ctx context.Context, filters ...header.Filter) (<-chan *header.ExtendedHeader, error) {
+	sub, err := header.SubscribeFiltered(s.sub, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(chan *header.ExtendedHeader)
+	go func() {
+		defer close(headers)
+		defer sub.Cancel()
+		for {
+			eh, err := sub.NextHeader(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Errorw("subscription: getting next header", "err", err)
+				}
+				return
+			}
+			select {
+			case headers <- eh:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return headers, nil
+}
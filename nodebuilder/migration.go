@@ -0,0 +1,214 @@
+package nodebuilder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+
+	"github.com/celestiaorg/celestia-node/header/store"
+)
+
+var (
+	// ErrSchemaOutdated is thrown on attempt to run the Node over the Datastore with pending migrations.
+	ErrSchemaOutdated = errors.New("node: datastore schema is outdated and must be migrated")
+	// ErrSchemaUnsupported is thrown on attempt to open the Datastore written by a newer version of the Node.
+	ErrSchemaUnsupported = errors.New("node: datastore schema is newer than supported")
+)
+
+// schemaVersionKey keeps the version of the Datastore schema.
+var schemaVersionKey = datastore.NewKey("node/schema_version")
+
+// Migration upgrades the layout of the data kept in the Datastore, e.g. header store keys,
+// DASer checkpoint, fraud proofs or sampling results, to the next schema version.
+type Migration struct {
+	// Version is the schema version the Migration upgrades to.
+	Version uint64
+	// Name briefly describes the Migration.
+	Name string
+	// Migrate performs the Migration over the Datastore.
+	Migrate func(context.Context, datastore.Batching) error
+}
+
+// migrations is the registry of Migrations ordered by their versions.
+// Any change to the data layout must append a Migration here with the next version,
+// so existing Datastores are upgraded instead of being wiped.
+// Versions must never be reordered or reused.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "header store: deduplicate validator sets",
+		Migrate: store.MigrateValidatorSets,
+	},
+	{
+		Version: 2,
+		Name:    "header store: index headers by time and data hash",
+		Migrate: store.MigrateIndexes,
+	},
+}
+
+// SchemaVersion reports the Datastore schema version supported by the Node.
+func SchemaVersion() uint64 {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// MigrationOption configures how pending Migrations are run.
+type MigrationOption func(*migrationOptions)
+
+type migrationOptions struct {
+	dryRun bool
+	backup bool
+}
+
+// WithMigrationDryRun only reports the pending Migrations, leaving the Datastore intact.
+// The Node refuses to run over the Datastore until it is migrated.
+func WithMigrationDryRun() MigrationOption {
+	return func(opts *migrationOptions) {
+		opts.dryRun = true
+	}
+}
+
+// WithMigrationBackup backs up the Datastore before running pending Migrations.
+// Only the FileSystem Store supports backups.
+func WithMigrationBackup() MigrationOption {
+	return func(opts *migrationOptions) {
+		opts.backup = true
+	}
+}
+
+// Migrate runs the pending Migrations over the Datastore of the given Store
+// and records the resulting schema version.
+// A fresh Datastore is considered to be of the latest schema version.
+func Migrate(ctx context.Context, s Store, opts ...MigrationOption) error {
+	options := &migrationOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	ds, err := s.Datastore()
+	if err != nil {
+		return err
+	}
+
+	version, err := readSchemaVersion(ctx, ds)
+	if err != nil {
+		return err
+	}
+
+	pending := pendingMigrations(version)
+	if len(pending) == 0 {
+		return nil
+	}
+	for _, m := range pending {
+		log.Infow("pending datastore migration", "version", m.Version, "name", m.Name, "dry_run", options.dryRun)
+	}
+	if options.dryRun {
+		return nil
+	}
+
+	if options.backup {
+		fs, ok := s.(*fsStore)
+		if !ok {
+			return fmt.Errorf("node: store does not support backups")
+		}
+
+		path, err := fs.backup(version)
+		if err != nil {
+			return fmt.Errorf("node: backing up datastore: %w", err)
+		}
+		log.Infow("backed up datastore", "path", path, "version", version)
+
+		// the datastore is closed for the backup, so reopen it
+		ds, err = s.Datastore()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, m := range pending {
+		log.Infow("migrating datastore", "version", m.Version, "name", m.Name)
+		err = m.Migrate(ctx, ds)
+		if err != nil {
+			return fmt.Errorf("node: migrating datastore to version %d (%s): %w", m.Version, m.Name, err)
+		}
+		// record the version after every migration, so the completed ones are not rerun on failure
+		err = writeSchemaVersion(ctx, ds, m.Version)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSchema ensures the Datastore of the given Store is of the supported schema version.
+func checkSchema(ctx context.Context, s Store) error {
+	ds, err := s.Datastore()
+	if err != nil {
+		return err
+	}
+
+	version, err := readSchemaVersion(ctx, ds)
+	if err != nil {
+		return err
+	}
+	if version < SchemaVersion() {
+		return fmt.Errorf("%w: version %d, supported %d", ErrSchemaOutdated, version, SchemaVersion())
+	}
+	return nil
+}
+
+// pendingMigrations returns the Migrations to be run over the Datastore of the given schema version.
+func pendingMigrations(version uint64) []Migration {
+	for i, m := range migrations {
+		if m.Version > version {
+			return migrations[i:]
+		}
+	}
+	return nil
+}
+
+// readSchemaVersion loads the schema version of the Datastore.
+// A fresh Datastore gets the latest schema version recorded, while the one written
+// before the version was tracked is of version zero.
+func readSchemaVersion(ctx context.Context, ds datastore.Batching) (uint64, error) {
+	b, err := ds.Get(ctx, schemaVersionKey)
+	switch err {
+	default:
+		return 0, err
+	case nil:
+		version, err := strconv.ParseUint(string(b), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("node: parsing datastore schema version: %w", err)
+		}
+		if version > SchemaVersion() {
+			return 0, fmt.Errorf("%w: version %d, supported %d", ErrSchemaUnsupported, version, SchemaVersion())
+		}
+		return version, nil
+	case datastore.ErrNotFound:
+	}
+
+	res, err := ds.Query(ctx, query.Query{KeysOnly: true, Limit: 1})
+	if err != nil {
+		return 0, err
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		return 0, err
+	}
+	if len(entries) != 0 {
+		return 0, nil
+	}
+
+	return SchemaVersion(), writeSchemaVersion(ctx, ds, SchemaVersion())
+}
+
+// writeSchemaVersion records the schema version of the Datastore.
+func writeSchemaVersion(ctx context.Context, ds datastore.Batching, version uint64) error {
+	return ds.Put(ctx, schemaVersionKey, []byte(strconv.FormatUint(version, 10)))
+}
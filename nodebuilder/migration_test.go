@@ -0,0 +1,105 @@
+package nodebuilder
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/celestia-node/nodebuilder/node"
+)
+
+func TestMigrationsOrdered(t *testing.T) {
+	for i, m := range migrations {
+		assert.EqualValues(t, i+1, m.Version, m.Name)
+		assert.NotEmpty(t, m.Name)
+		assert.NotNil(t, m.Migrate)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+
+	var migrated []uint64
+	migration := func(version uint64) Migration {
+		return Migration{
+			Version: version,
+			Name:    "test",
+			Migrate: func(context.Context, datastore.Batching) error {
+				migrated = append(migrated, version)
+				return nil
+			},
+		}
+	}
+	defer func(orig []Migration) {
+		migrations = orig
+	}(migrations)
+	migrations = []Migration{migration(1), migration(2)}
+
+	t.Run("Fresh", func(t *testing.T) {
+		migrated = nil
+		store := NewMemStore()
+
+		err := Migrate(ctx, store)
+		require.NoError(t, err)
+		assert.Empty(t, migrated)
+		assert.NoError(t, checkSchema(ctx, store))
+	})
+
+	t.Run("Unversioned", func(t *testing.T) {
+		migrated = nil
+		store := NewMemStore()
+		ds, err := store.Datastore()
+		require.NoError(t, err)
+		require.NoError(t, ds.Put(ctx, datastore.NewKey("data"), []byte("data")))
+
+		err = Migrate(ctx, store, WithMigrationDryRun())
+		require.NoError(t, err)
+		assert.Empty(t, migrated)
+		assert.ErrorIs(t, checkSchema(ctx, store), ErrSchemaOutdated)
+
+		err = Migrate(ctx, store)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{1, 2}, migrated)
+		assert.NoError(t, checkSchema(ctx, store))
+
+		// only new migrations are run
+		migrations = append(migrations, migration(3))
+		err = Migrate(ctx, store)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{1, 2, 3}, migrated)
+		migrations = migrations[:2]
+
+		// the datastore is of the newer version now
+		err = Migrate(ctx, store)
+		assert.ErrorIs(t, err, ErrSchemaUnsupported)
+	})
+
+	t.Run("Backup", func(t *testing.T) {
+		migrated = nil
+		dir := t.TempDir()
+		err := Init(*DefaultConfig(node.Light), dir, node.Light)
+		require.NoError(t, err)
+
+		store, err := OpenStore(dir)
+		require.NoError(t, err)
+		ds, err := store.Datastore()
+		require.NoError(t, err)
+		require.NoError(t, writeSchemaVersion(ctx, ds, 1))
+		require.NoError(t, store.Close())
+
+		// backup is taken only if there are pending migrations
+		store, err = OpenStore(dir, WithMigrationBackup())
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{2}, migrated)
+		require.NoError(t, checkSchema(ctx, store))
+		require.NoError(t, store.Close())
+
+		backups, err := filepath.Glob(filepath.Join(dir, "data-backup-v1-*"))
+		require.NoError(t, err)
+		assert.Len(t, backups, 1)
+	})
+}
@@ -78,6 +78,11 @@ func New(tp node.Type, store Store, options ...fx.Option) (*Node, error) {
 
 // NewWithConfig assembles a new Node with the given type 'tp' over Store 'store' and a custom config.
 func NewWithConfig(tp node.Type, store Store, cfg *Config, options ...fx.Option) (*Node, error) {
+	err := checkSchema(context.Background(), store)
+	if err != nil {
+		return nil, err
+	}
+
 	opts := append([]fx.Option{ConstructModule(tp, cfg, store)}, options...)
 	return newNode(opts...)
 }
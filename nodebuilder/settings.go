@@ -18,6 +18,7 @@ import (
 	"github.com/celestiaorg/celestia-node/fraud"
 	"github.com/celestiaorg/celestia-node/header"
 	"github.com/celestiaorg/celestia-node/nodebuilder/daser"
+	headerServ "github.com/celestiaorg/celestia-node/nodebuilder/header"
 	"github.com/celestiaorg/celestia-node/nodebuilder/node"
 	"github.com/celestiaorg/celestia-node/params"
 	"github.com/celestiaorg/celestia-node/state"
@@ -40,6 +41,7 @@ func WithMetrics(metricOpts []otlpmetrichttp.Option, nodeType node.Type) fx.Opti
 		fx.Supply(metricOpts),
 		fx.Invoke(initializeMetrics),
 		fx.Invoke(header.WithMetrics),
+		headerServ.WithMetrics(),
 		fx.Invoke(state.WithMetrics),
 		fx.Invoke(fraud.WithMetrics),
 	)
@@ -1,10 +1,15 @@
 package nodebuilder
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/dgraph-io/badger/v2/options"
 	"github.com/ipfs/go-datastore"
@@ -48,7 +53,8 @@ type Store interface {
 // To be opened the Store must be initialized first, otherwise ErrNotInited is thrown.
 // OpenStore takes a file Lock on directory, hence only one Store can be opened at a time under the given 'path',
 // otherwise ErrOpened is thrown.
-func OpenStore(path string) (Store, error) {
+// OpenStore runs pending Migrations over the Datastore, configured by the given MigrationOptions.
+func OpenStore(path string, opts ...MigrationOption) (Store, error) {
 	path, err := storePath(path)
 	if err != nil {
 		return nil, err
@@ -68,10 +74,18 @@ func OpenStore(path string) (Store, error) {
 		return nil, ErrNotInited
 	}
 
-	return &fsStore{
+	store := &fsStore{
 		path:    path,
 		dirLock: flock,
-	}, nil
+	}
+
+	err = Migrate(context.Background(), store, opts...)
+	if err != nil {
+		store.Close() //nolint: errcheck
+		return nil, err
+	}
+
+	return store, nil
 }
 
 func (f *fsStore) Path() string {
@@ -161,9 +175,66 @@ func (f *fsStore) Datastore() (_ datastore.Batching, err error) {
 
 func (f *fsStore) Close() error {
 	defer f.dirLock.Unlock() //nolint: errcheck
+	if f.data == nil {
+		return nil
+	}
 	return f.data.Close()
 }
 
+// backup closes the Datastore and copies it aside, returning the path of the copy.
+// The Datastore is reopened on the next access.
+func (f *fsStore) backup(version uint64) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.data != nil {
+		err := f.data.Close()
+		if err != nil {
+			return "", err
+		}
+		f.data = nil
+	}
+
+	path := backupPath(f.path, version, time.Now())
+	return path, copyDir(dataPath(f.path), path)
+}
+
+// copyDir recursively copies the directory with all its files.
+func copyDir(from, to string) error {
+	return filepath.WalkDir(from, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, perms)
+		}
+
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+
+		dst, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(dst, src)
+		if err != nil {
+			dst.Close() //nolint: errcheck
+			return err
+		}
+		return dst.Close()
+	})
+}
+
 type fsStore struct {
 	path string
 
@@ -193,3 +264,7 @@ func keysPath(base string) string {
 func dataPath(base string) string {
 	return filepath.Join(base, "data")
 }
+
+func backupPath(base string, version uint64, t time.Time) string {
+	return filepath.Join(base, fmt.Sprintf("data-backup-v%d-%d", version, t.Unix()))
+}
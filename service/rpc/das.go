@@ -2,11 +2,18 @@ package rpc
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/celestiaorg/celestia-node/das"
 )
 
 const (
-	dasStateEndpoint = "/daser/state"
+	dasStateEndpoint   = "/daser/state"
+	dasResultsEndpoint = "/daser/results"
 )
 
 func (h *Handler) handleDASStateRequest(w http.ResponseWriter, r *http.Request) {
@@ -25,3 +32,40 @@ func (h *Handler) handleDASStateRequest(w http.ResponseWriter, r *http.Request)
 		log.Errorw("serving request", "endpoint", dasStateEndpoint, "err", err)
 	}
 }
+
+// handleDASResultsRequest responds with the records of sampling of the sampled heights in the range [from:to).
+func (h *Handler) handleDASResultsRequest(w http.ResponseWriter, r *http.Request) {
+	// read and parse request
+	vars := mux.Vars(r)
+	from, err := strconv.ParseUint(vars[fromKey], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, dasResultsEndpoint, err)
+		return
+	}
+	to, err := strconv.ParseUint(vars[toKey], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, dasResultsEndpoint, err)
+		return
+	}
+	if from == 0 || from >= to || to-from > das.MaxResultsRange {
+		writeError(w, http.StatusBadRequest, dasResultsEndpoint,
+			fmt.Errorf("invalid range of heights [%d:%d), at most %d heights can be requested",
+				from, to, das.MaxResultsRange))
+		return
+	}
+	// perform request
+	results, err := h.das.SamplingResults(r.Context(), from, to)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, dasResultsEndpoint, err)
+		return
+	}
+	resp, err := json.Marshal(results)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, dasResultsEndpoint, err)
+		return
+	}
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Errorw("serving request", "endpoint", dasResultsEndpoint, "err", err)
+	}
+}
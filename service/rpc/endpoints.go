@@ -41,6 +41,22 @@ func (h *Handler) RegisterEndpoints(rpc *Server) {
 		h.handleHeightAvailabilityRequest, http.MethodGet)
 
 	// header endpoints
+	// must be registered before the header by height endpoint to not be shadowed by it
+	rpc.RegisterHandlerFunc(headerConflictsEndpoint, h.handleHeaderConflictsRequest, http.MethodGet)
+	rpc.RegisterHandlerFunc(syncStateEndpoint, h.handleSyncStateRequest, http.MethodGet)
+	rpc.RegisterHandlerFunc(syncHistoryEndpoint, h.handleSyncHistoryRequest, http.MethodGet)
+	rpc.RegisterHandlerFunc(headerSubscribeEndpoint, h.handleSubscribeRequest, http.MethodGet)
+	rpc.RegisterHandlerFunc(headerParamsEndpoint, h.handleNetworkParamsRequest, http.MethodGet)
+	rpc.RegisterHandlerFunc(fmt.Sprintf("%s/{%s}", headerByTimeEndpoint, unixKey), h.handleHeaderByTimeRequest,
+		http.MethodGet)
+	rpc.RegisterHandlerFunc(fmt.Sprintf("%s/{%s}", headerByDataEndpoint, dataKey), h.handleHeaderByDataHashRequest,
+		http.MethodGet)
+	rpc.RegisterHandlerFunc(fmt.Sprintf("%s/{%s}", headerWaitEndpoint, heightKey), h.handleWaitForHeightRequest,
+		http.MethodGet)
+	rpc.RegisterHandlerFunc(fmt.Sprintf("%s/{%s}", headerByHashEndpoint, hashKey), h.handleHeaderByHashRequest,
+		http.MethodGet)
+	rpc.RegisterHandlerFunc(fmt.Sprintf("%s/{%s}/{%s}", headerRangeEndpoint, fromKey, amountKey),
+		h.handleHeaderRangeRequest, http.MethodGet)
 	rpc.RegisterHandlerFunc(fmt.Sprintf("%s/{%s}", headerByHeightEndpoint, heightKey), h.handleHeaderRequest,
 		http.MethodGet)
 	rpc.RegisterHandlerFunc(headEndpoint, h.handleHeadRequest, http.MethodGet)
@@ -49,5 +65,7 @@ func (h *Handler) RegisterEndpoints(rpc *Server) {
 	// only register if DASer service is available
 	if h.das != nil {
 		rpc.RegisterHandlerFunc(dasStateEndpoint, h.handleDASStateRequest, http.MethodGet)
+		rpc.RegisterHandlerFunc(fmt.Sprintf("%s/{%s}/{%s}", dasResultsEndpoint, fromKey, toKey),
+			h.handleDASResultsRequest, http.MethodGet)
 	}
 }
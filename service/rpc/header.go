@@ -1,22 +1,77 @@
 package rpc
 
 import (
+	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	"github.com/celestiaorg/nmt/namespace"
+
 	"github.com/celestiaorg/celestia-node/header"
+	"github.com/celestiaorg/celestia-node/header/netparams"
+	"github.com/celestiaorg/celestia-node/share"
 )
 
 const (
-	headEndpoint           = "/head"
-	headerByHeightEndpoint = "/header"
+	headEndpoint            = "/head"
+	headerByHeightEndpoint  = "/header"
+	headerConflictsEndpoint = "/header/conflicts"
+	headerByTimeEndpoint    = "/header/time"
+	headerByDataEndpoint    = "/header/data_hash"
+	syncStateEndpoint       = "/header/sync/state"
+	syncHistoryEndpoint     = "/header/sync/history"
+	headerByHashEndpoint    = "/header/hash"
+	headerRangeEndpoint     = "/header/range"
+	headerWaitEndpoint      = "/header/wait"
+	headerSubscribeEndpoint = "/header/subscribe"
+	headerParamsEndpoint    = "/header/params"
+)
+
+// binaryContentType is the content type to be accepted by the request to get headers
+// marshaled with header.MarshalExtendedHeader instead of JSON, which can't be unmarshalled back.
+// Ranges of headers are written one by one with header.WriteDelimited.
+const binaryContentType = "application/octet-stream"
+
+// maxHeaderRange is the maximum amount of headers served by a single range request.
+var maxHeaderRange uint64 = 512
+
+var (
+	// defaultWaitTimeout is the time a request waiting for a header is held for, unless set by the request.
+	defaultWaitTimeout = time.Second * 30
+	// maxWaitTimeout is the maximum time a request waiting for a header can be held for.
+	maxWaitTimeout = time.Minute * 5
 )
 
 var (
 	heightKey = "height"
+	unixKey   = "unix"
+	dataKey   = "data_hash"
+	hashKey   = "hash"
+	fromKey   = "from"
+	toKey     = "to"
+	amountKey = "amount"
+	// timeoutKey is the optional query parameter setting the time to wait for a header, e.g. '1m'.
+	timeoutKey = "timeout"
+	// lookupKey is the optional query parameter choosing the header by time,
+	// either 'exact', 'floor' or 'ceiling'. Defaults to 'floor'.
+	lookupKey = "lookup"
+	// namespaceKey is the optional query parameter of the subscription to the headers which may have data
+	// of the namespace, given in hex, or of the inclusive range of namespaces, given as '<from>-<to>'.
+	namespaceKey = "namespace"
+	// emptyKey is the optional query parameter of the subscription to the headers of either empty
+	// or non-empty blocks, i.e. 'true' or 'false'.
+	emptyKey = "empty"
+	// minSquareSizeKey is the optional query parameter of the subscription to the headers of blocks
+	// with the original data square of at least the given size.
+	minSquareSizeKey = "min_square_size"
 )
 
 func (h *Handler) handleHeadRequest(w http.ResponseWriter, r *http.Request) {
@@ -25,33 +80,295 @@ func (h *Handler) handleHeadRequest(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusInternalServerError, headEndpoint, err)
 		return
 	}
-	resp, err := json.Marshal(head)
+	writeHeader(w, r, headEndpoint, head)
+}
+
+func (h *Handler) handleHeaderRequest(w http.ResponseWriter, r *http.Request) {
+	header, err := h.performGetHeaderRequest(w, r, headerByHeightEndpoint)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, headEndpoint, err)
+		// return here as we've already logged and written the error
+		return
+	}
+	writeHeader(w, r, headerByHeightEndpoint, header)
+}
+
+// handleWaitForHeightRequest holds the request until the header at the requested height is stored,
+// responding with it right away, or until the timeout, responding with the request timeout status.
+func (h *Handler) handleWaitForHeightRequest(w http.ResponseWriter, r *http.Request) {
+	// read and parse request
+	height, err := strconv.ParseUint(mux.Vars(r)[heightKey], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, headerWaitEndpoint, err)
+		return
+	}
+	timeout := defaultWaitTimeout
+	if timeoutStr := r.URL.Query().Get(timeoutKey); timeoutStr != "" {
+		timeout, err = time.ParseDuration(timeoutStr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, headerWaitEndpoint, err)
+			return
+		}
+		if timeout <= 0 || timeout > maxWaitTimeout {
+			writeError(w, http.StatusBadRequest, headerWaitEndpoint,
+				fmt.Errorf("timeout must be positive and not exceed %v", maxWaitTimeout))
+			return
+		}
+	}
+	// perform request, which GetByHeight holds until the header is stored
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	eh, err := h.header.GetByHeight(ctx, height)
+	if err != nil {
+		status := headerErrorStatus(err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			status = http.StatusRequestTimeout
+		}
+		writeError(w, status, headerWaitEndpoint, err)
+		return
+	}
+	writeHeader(w, r, headerWaitEndpoint, eh)
+}
+
+// handleSubscribeRequest streams the new headers matching the filters given in the query
+// until the request is canceled. The headers are written as newline-delimited JSON,
+// or with header.WriteDelimited, if the binary format is accepted.
+func (h *Handler) handleSubscribeRequest(w http.ResponseWriter, r *http.Request) {
+	// read and parse request
+	filters, err := parseFilters(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, headerSubscribeEndpoint, err)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, headerSubscribeEndpoint,
+			errors.New("streaming is not supported"))
+		return
+	}
+	// perform request
+	headers, err := h.header.Subscribe(r.Context(), filters...)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, headerSubscribeEndpoint, err)
+		return
+	}
+
+	binary := acceptsBinary(r)
+	if binary {
+		w.Header().Set("Content-Type", binaryContentType)
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	// let the client know the subscription is started
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for eh := range headers {
+		if binary {
+			err = header.WriteDelimited(w, eh)
+		} else {
+			err = json.NewEncoder(w).Encode(eh)
+		}
+		if err != nil {
+			log.Errorw("writing response", "endpoint", headerSubscribeEndpoint, "err", err)
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func (h *Handler) handleHeaderByHashRequest(w http.ResponseWriter, r *http.Request) {
+	// read and parse request
+	hash, err := hex.DecodeString(mux.Vars(r)[hashKey])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, headerByHashEndpoint, err)
+		return
+	}
+	// perform request
+	eh, err := h.header.GetByHash(r.Context(), hash)
+	if err != nil {
+		writeError(w, headerErrorStatus(err), headerByHashEndpoint, err)
+		return
+	}
+	writeHeader(w, r, headerByHashEndpoint, eh)
+}
+
+func (h *Handler) handleHeaderRangeRequest(w http.ResponseWriter, r *http.Request) {
+	// read and parse request
+	vars := mux.Vars(r)
+	from, err := strconv.ParseUint(vars[fromKey], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, headerRangeEndpoint, err)
+		return
+	}
+	amount, err := strconv.ParseUint(vars[amountKey], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, headerRangeEndpoint, err)
+		return
+	}
+	if from == 0 || amount == 0 {
+		writeError(w, http.StatusBadRequest, headerRangeEndpoint,
+			fmt.Errorf("invalid range of %d headers from %d", amount, from))
+		return
+	}
+	if amount > maxHeaderRange {
+		writeError(w, http.StatusBadRequest, headerRangeEndpoint, header.ErrHeadersLimitExceeded)
+		return
+	}
+	// only the stored headers are served, so the request does not wait for the future ones
+	head, err := h.header.Head(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, headerRangeEndpoint, err)
+		return
+	}
+	if from > uint64(head.Height) {
+		writeError(w, http.StatusNotFound, headerRangeEndpoint, header.ErrNotFound)
+		return
+	}
+	to := from + amount
+	if to > uint64(head.Height)+1 {
+		to = uint64(head.Height) + 1
+	}
+	// perform request
+	headers, err := h.header.GetRangeByHeight(r.Context(), from, to)
+	if err != nil {
+		writeError(w, headerErrorStatus(err), headerRangeEndpoint, err)
+		return
+	}
+
+	if acceptsBinary(r) {
+		w.Header().Set("Content-Type", binaryContentType)
+		for _, eh := range headers {
+			err = header.WriteDelimited(w, eh)
+			if err != nil {
+				log.Errorw("writing response", "endpoint", headerRangeEndpoint, "err", err)
+				return
+			}
+		}
+		return
+	}
+	resp, err := json.Marshal(headers)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, headerRangeEndpoint, err)
+		return
+	}
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Errorw("writing response", "endpoint", headerRangeEndpoint, "err", err)
+	}
+}
+
+func (h *Handler) handleHeaderByTimeRequest(w http.ResponseWriter, r *http.Request) {
+	// read and parse request
+	t, err := parseUnixTime(mux.Vars(r)[unixKey])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, headerByTimeEndpoint, err)
+		return
+	}
+	lookup := header.TimeFloor
+	if lookupStr := r.URL.Query().Get(lookupKey); lookupStr != "" {
+		lookup, err = header.ParseTimeLookup(lookupStr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, headerByTimeEndpoint, err)
+			return
+		}
+	}
+	// perform request
+	eh, err := h.header.GetByTime(r.Context(), t, lookup)
+	if err != nil {
+		writeError(w, headerErrorStatus(err), headerByTimeEndpoint, err)
+		return
+	}
+	writeHeader(w, r, headerByTimeEndpoint, eh)
+}
+
+func (h *Handler) handleHeaderByDataHashRequest(w http.ResponseWriter, r *http.Request) {
+	// read and parse request
+	hash, err := hex.DecodeString(mux.Vars(r)[dataKey])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, headerByDataEndpoint, err)
+		return
+	}
+	// perform request
+	eh, err := h.header.GetByDataHash(r.Context(), hash)
+	if err != nil {
+		writeError(w, headerErrorStatus(err), headerByDataEndpoint, err)
+		return
+	}
+	writeHeader(w, r, headerByDataEndpoint, eh)
+}
+
+func (h *Handler) handleHeaderConflictsRequest(w http.ResponseWriter, r *http.Request) {
+	conflicts, err := h.header.Conflicts(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, headerConflictsEndpoint, err)
+		return
+	}
+	resp, err := json.Marshal(conflicts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, headerConflictsEndpoint, err)
 		return
 	}
 	_, err = w.Write(resp)
 	if err != nil {
-		log.Errorw("writing response", "endpoint", headEndpoint, "err", err)
+		log.Errorw("writing response", "endpoint", headerConflictsEndpoint, "err", err)
 		return
 	}
 }
 
-func (h *Handler) handleHeaderRequest(w http.ResponseWriter, r *http.Request) {
-	header, err := h.performGetHeaderRequest(w, r, headerByHeightEndpoint)
+func (h *Handler) handleSyncStateRequest(w http.ResponseWriter, r *http.Request) {
+	state, err := h.header.SyncState(r.Context())
 	if err != nil {
-		// return here as we've already logged and written the error
+		writeError(w, http.StatusInternalServerError, syncStateEndpoint, err)
+		return
+	}
+	resp, err := json.Marshal(state)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, syncStateEndpoint, err)
+		return
+	}
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Errorw("writing response", "endpoint", syncStateEndpoint, "err", err)
+		return
+	}
+}
+
+func (h *Handler) handleNetworkParamsRequest(w http.ResponseWriter, r *http.Request) {
+	params, err := h.header.NetworkParams(r.Context())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, netparams.ErrUnknownParams) {
+			status = http.StatusNotFound
+		}
+		writeError(w, status, headerParamsEndpoint, err)
+		return
+	}
+	resp, err := json.Marshal(params)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, headerParamsEndpoint, err)
 		return
 	}
-	// marshal and write response
-	resp, err := json.Marshal(header)
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Errorw("writing response", "endpoint", headerParamsEndpoint, "err", err)
+		return
+	}
+}
+
+func (h *Handler) handleSyncHistoryRequest(w http.ResponseWriter, r *http.Request) {
+	history, err := h.header.SyncHistory(r.Context())
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, headerByHeightEndpoint, err)
+		writeError(w, http.StatusInternalServerError, syncHistoryEndpoint, err)
+		return
+	}
+	resp, err := json.Marshal(history)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, syncHistoryEndpoint, err)
 		return
 	}
 	_, err = w.Write(resp)
 	if err != nil {
-		log.Errorw("writing response", "endpoint", headerByHeightEndpoint, "err", err)
+		log.Errorw("writing response", "endpoint", syncHistoryEndpoint, "err", err)
 		return
 	}
 }
@@ -70,10 +387,140 @@ func (h *Handler) performGetHeaderRequest(
 		return nil, err
 	}
 	// perform request
-	header, err := h.header.GetByHeight(r.Context(), uint64(height))
+	eh, err := h.header.GetByHeight(r.Context(), uint64(height))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, header.ErrPruned) {
+			status = http.StatusGone
+		}
+		writeError(w, status, endpoint, err)
+		return nil, err
+	}
+	return eh, nil
+}
+
+// parseFilters parses the header filters from the query of the subscription request.
+func parseFilters(r *http.Request) ([]header.Filter, error) {
+	query := r.URL.Query()
+	filters := make([]header.Filter, 0, 3)
+	if nsStr := query.Get(namespaceKey); nsStr != "" {
+		fromStr, toStr, isRange := strings.Cut(nsStr, "-")
+		from, err := parseNamespace(fromStr)
+		if err != nil {
+			return nil, err
+		}
+		to := from
+		if isRange {
+			to, err = parseNamespace(toStr)
+			if err != nil {
+				return nil, err
+			}
+			if to.Less(from) {
+				return nil, fmt.Errorf("invalid namespace range: %s", nsStr)
+			}
+		}
+		filters = append(filters, header.NamespaceRange(from, to))
+	}
+	if emptyStr := query.Get(emptyKey); emptyStr != "" {
+		empty, err := strconv.ParseBool(emptyStr)
+		if err != nil {
+			return nil, err
+		}
+		if empty {
+			filters = append(filters, header.Empty())
+		} else {
+			filters = append(filters, header.NonEmpty())
+		}
+	}
+	if sizeStr := query.Get(minSquareSizeKey); sizeStr != "" {
+		size, err := strconv.ParseUint(sizeStr, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, header.MinSquareSize(int(size)))
+	}
+	return filters, nil
+}
+
+// parseNamespace parses the namespace ID given in hex.
+func parseNamespace(s string) (namespace.ID, error) {
+	nID, err := hex.DecodeString(s)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, endpoint, err)
 		return nil, err
 	}
-	return header, nil
+	if len(nID) != share.NamespaceSize {
+		return nil, fmt.Errorf("expected namespace ID of size %d, got %d", share.NamespaceSize, len(nID))
+	}
+	return nID, nil
+}
+
+// writeHeader writes the header in the format accepted by the request, either JSON or binary.
+func writeHeader(w http.ResponseWriter, r *http.Request, endpoint string, eh *header.ExtendedHeader) {
+	var (
+		resp []byte
+		err  error
+	)
+	binary := acceptsBinary(r)
+	if binary {
+		resp, err = header.MarshalExtendedHeader(eh)
+	} else {
+		resp, err = json.Marshal(eh)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, endpoint, err)
+		return
+	}
+	if binary {
+		w.Header().Set("Content-Type", binaryContentType)
+	}
+	_, err = w.Write(resp)
+	if err != nil {
+		log.Errorw("writing response", "endpoint", endpoint, "err", err)
+	}
+}
+
+// acceptsBinary checks whether the request accepts headers in the binary format.
+func acceptsBinary(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), binaryContentType)
+}
+
+// headerErrorStatus returns the response status for the error of getting headers.
+func headerErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, header.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, header.ErrPruned):
+		return http.StatusGone
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseUnixTime parses the unix time in seconds with the optional fractional part
+// up to nanoseconds, e.g. '1665000000' or '1665000000.123456789'.
+func parseUnixTime(s string) (time.Time, error) {
+	secStr, nsecStr, _ := strings.Cut(s, ".")
+	sec, err := strconv.ParseInt(secStr, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var nsec int64
+	if nsecStr != "" {
+		if len(nsecStr) > 9 {
+			return time.Time{}, fmt.Errorf("unix time precision exceeds nanoseconds: %s", s)
+		}
+		// unlike the seconds, the fractional part must be unsigned
+		var frac uint64
+		frac, err = strconv.ParseUint(nsecStr+strings.Repeat("0", 9-len(nsecStr)), 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		nsec = int64(frac)
+		// the fractional part goes in the same direction as the seconds, e.g. '-1.5' is 1.5s before the epoch
+		if strings.HasPrefix(secStr, "-") {
+			nsec = -nsec
+		}
+	}
+	return time.Unix(sec, nsec).UTC(), nil
 }
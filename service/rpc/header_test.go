@@ -0,0 +1,279 @@
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	tmrand "github.com/tendermint/tendermint/libs/rand"
+
+	"github.com/celestiaorg/celestia-app/pkg/da"
+	"github.com/celestiaorg/nmt"
+
+	"github.com/celestiaorg/celestia-node/header"
+	headerrpc "github.com/celestiaorg/celestia-node/header/rpc"
+	"github.com/celestiaorg/celestia-node/header/store"
+	headerServ "github.com/celestiaorg/celestia-node/nodebuilder/header"
+	"github.com/celestiaorg/celestia-node/share"
+)
+
+func TestHeaderExchange(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+	in := append([]*header.ExtendedHeader{suite.Head()}, suite.GenExtendedHeaders(29)...)
+	s := store.NewTestStore(ctx, t, in[0])
+	_, err := s.Append(ctx, in[1:]...)
+	require.NoError(t, err)
+	_, err = s.GetByHeight(ctx, 30)
+	require.NoError(t, err)
+
+	server := NewServer(Config{Address: "127.0.0.1", Port: "0"})
+	require.NoError(t, server.Start(ctx))
+	t.Cleanup(func() {
+		server.Stop(ctx) //nolint:errcheck
+	})
+	NewHandler(nil, nil, headerServ.NewHeaderService(nil, nil, nil, nil, s, nil), nil).RegisterEndpoints(server)
+	ex := headerrpc.NewExchange(fmt.Sprintf("http://%s/", server.listener.Addr().String()))
+
+	head, err := ex.Head(ctx)
+	require.NoError(t, err)
+	assert.True(t, in[29].Equals(head))
+
+	h, err := ex.GetByHeight(ctx, 10)
+	require.NoError(t, err)
+	assert.True(t, in[9].Equals(h))
+
+	h, err = ex.Get(ctx, in[4].Hash())
+	require.NoError(t, err)
+	assert.True(t, in[4].Equals(h))
+
+	_, err = ex.Get(ctx, suite.GenExtendedHeaders(1)[0].Hash())
+	require.ErrorIs(t, err, header.ErrNotFound)
+
+	hs, err := ex.GetRangeByHeight(ctx, 5, 20)
+	require.NoError(t, err)
+	require.Len(t, hs, 20)
+	for i, h := range hs {
+		assert.True(t, in[i+4].Equals(h))
+	}
+
+	// only the stored headers are served
+	hs, err = ex.GetRangeByHeight(ctx, 25, 20)
+	require.NoError(t, err)
+	require.Len(t, hs, 6)
+	assert.Equal(t, int64(30), hs[5].Height)
+
+	_, err = ex.GetRangeByHeight(ctx, 31, 20)
+	require.ErrorIs(t, err, header.ErrNotFound)
+}
+
+func TestHeaderWaitForHeight(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+	s := store.NewTestStore(ctx, t, suite.Head())
+
+	server := NewServer(Config{Address: "127.0.0.1", Port: "0"})
+	require.NoError(t, server.Start(ctx))
+	t.Cleanup(func() {
+		server.Stop(ctx) //nolint:errcheck
+	})
+	NewHandler(nil, nil, headerServ.NewHeaderService(nil, nil, nil, nil, s, nil), nil).RegisterEndpoints(server)
+
+	wait := func(query string) (*http.Response, []byte) {
+		url := fmt.Sprintf("http://%s%s/2%s", server.listener.Addr().String(), headerWaitEndpoint, query)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", binaryContentType)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, body
+	}
+
+	resp, _ := wait("?timeout=100ms")
+	assert.Equal(t, http.StatusRequestTimeout, resp.StatusCode)
+	resp, _ = wait("?timeout=1h")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// the header is responded as soon as it is stored
+	next := suite.GenExtendedHeaders(1)[0]
+	go func() {
+		time.Sleep(time.Millisecond * 100)
+		_, err := s.Append(ctx, next)
+		assert.NoError(t, err)
+	}()
+	resp, body := wait("")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	h, err := header.UnmarshalExtendedHeader(body)
+	require.NoError(t, err)
+	assert.True(t, next.Equals(h))
+}
+
+// prunedStore pretends all the headers looked up by time or data hash to be pruned.
+type prunedStore struct {
+	header.Store
+}
+
+func (s *prunedStore) GetByTime(context.Context, time.Time, header.TimeLookup) (*header.ExtendedHeader, error) {
+	return nil, header.ErrPruned
+}
+
+func (s *prunedStore) GetByDataHash(context.Context, tmbytes.HexBytes) (*header.ExtendedHeader, error) {
+	return nil, header.ErrPruned
+}
+
+func TestHeaderLookupStatus(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+	head := suite.Head()
+	s := store.NewTestStore(ctx, t, head)
+
+	server := NewServer(Config{Address: "127.0.0.1", Port: "0"})
+	require.NoError(t, server.Start(ctx))
+	t.Cleanup(func() {
+		server.Stop(ctx) //nolint:errcheck
+	})
+	NewHandler(nil, nil, headerServ.NewHeaderService(nil, nil, nil, nil, s, nil), nil).RegisterEndpoints(server)
+	pruned := NewServer(Config{Address: "127.0.0.1", Port: "0"})
+	require.NoError(t, pruned.Start(ctx))
+	t.Cleanup(func() {
+		pruned.Stop(ctx) //nolint:errcheck
+	})
+	NewHandler(nil, nil, headerServ.NewHeaderService(nil, nil, nil, nil, &prunedStore{s}, nil), nil).
+		RegisterEndpoints(pruned)
+
+	get := func(server *Server, path string) (*http.Response, []byte) {
+		url := fmt.Sprintf("http://%s%s", server.listener.Addr().String(), path)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", binaryContentType)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, body
+	}
+
+	byTime := func(unix int64) string {
+		return fmt.Sprintf("%s/%d", headerByTimeEndpoint, unix)
+	}
+	byDataHash := func(hash tmbytes.HexBytes) string {
+		return fmt.Sprintf("%s/%s", headerByDataEndpoint, hash)
+	}
+
+	// the floor lookup of the next second, as the unix time is truncated to seconds
+	resp, body := get(server, byTime(head.Time.Unix()+1))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	h, err := header.UnmarshalExtendedHeader(body)
+	require.NoError(t, err)
+	assert.True(t, head.Equals(h))
+
+	resp, body = get(server, byDataHash(head.DataHash))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	h, err = header.UnmarshalExtendedHeader(body)
+	require.NoError(t, err)
+	assert.True(t, head.Equals(h))
+
+	resp, _ = get(server, byTime(head.Time.Add(-time.Hour).Unix()))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp, _ = get(server, byDataHash(tmrand.Bytes(32)))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = get(pruned, byTime(head.Time.Unix()))
+	assert.Equal(t, http.StatusGone, resp.StatusCode)
+	resp, _ = get(pruned, byDataHash(head.DataHash))
+	assert.Equal(t, http.StatusGone, resp.StatusCode)
+}
+
+func TestHeaderSubscribe(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	t.Cleanup(cancel)
+
+	suite := header.NewTestSuite(t, 3)
+	headers := suite.GenExtendedHeaders(3)
+	dah := da.NewDataAvailabilityHeader(share.RandEDS(t, 4))
+	headers = append(headers, suite.GenExtendedHeaderWithDAH(&dah))
+	headers = append(headers, suite.GenExtendedHeaders(3)...)
+	nID := nmt.MinNamespace(dah.RowsRoots[0], share.NamespaceSize)
+
+	server := NewServer(Config{Address: "127.0.0.1", Port: "0"})
+	require.NoError(t, server.Start(ctx))
+	t.Cleanup(func() {
+		server.Stop(ctx) //nolint:errcheck
+	})
+	sub := &header.DummySubscriber{Headers: headers}
+	NewHandler(nil, nil, headerServ.NewHeaderService(nil, sub, nil, nil, nil, nil), nil).RegisterEndpoints(server)
+
+	subscribe := func(query string) (*http.Response, []byte) {
+		url := fmt.Sprintf("http://%s%s?%s", server.listener.Addr().String(), headerSubscribeEndpoint, query)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", binaryContentType)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		// the stream is ended once the subscriber runs out of headers
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, body
+	}
+
+	resp, _ := subscribe("namespace=00")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, body := subscribe(fmt.Sprintf("namespace=%x&empty=false&min_square_size=2", nID))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	r := bufio.NewReader(bytes.NewReader(body))
+	h, err := header.ReadDelimited(r)
+	require.NoError(t, err)
+	assert.True(t, headers[3].Equals(h))
+	_, err = header.ReadDelimited(r)
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func Test_parseUnixTime(t *testing.T) {
+	tests := []struct {
+		in  string
+		out time.Time
+		err bool
+	}{
+		{in: "1665000000", out: time.Unix(1665000000, 0)},
+		{in: "1665000000.123456789", out: time.Unix(1665000000, 123456789)},
+		{in: "1665000000.5", out: time.Unix(1665000000, 500000000)},
+		{in: "-1.5", out: time.Unix(-1, -500000000)},
+		{in: "-0.5", out: time.Unix(0, -500000000)},
+		{in: "1.-5", err: true},
+		{in: "1.+5", err: true},
+		{in: "1.1234567891", err: true},
+		{in: "1.a", err: true},
+		{in: "", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			out, err := parseUnixTime(tt.in)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.out.Equal(out), out)
+		})
+	}
+}
@@ -29,3 +29,15 @@ type Availability interface {
 	// TODO(@Wondertan): Merge with SharesAvailable method, eventually
 	ProbabilityOfAvailability() float64
 }
+
+// SampleCounter is optionally implemented by Availability to report the amount of Shares
+// SharesAvailable requests to validate availability of the given Root.
+type SampleCounter interface {
+	SampleAmount(*Root) int
+}
+
+// SampleCache is optionally implemented by Availability to report whether availability
+// of the given Root was validated before, so SharesAvailable does not sample it again.
+type SampleCache interface {
+	IsCached(context.Context, *Root) (bool, error)
+}
@@ -53,16 +53,8 @@ func NewShareAvailability(avail share.Availability, ds datastore.Batching) *Shar
 
 // SharesAvailable will store, upon success, the hash of the given Root to disk.
 func (ca *ShareAvailability) SharesAvailable(ctx context.Context, root *share.Root) error {
-	// short-circuit if the given root is minimum DAH of an empty data square
-	if isMinRoot(root) {
-		return nil
-	}
 	// do not sample over Root that has already been sampled
-	key := rootKey(root)
-
-	ca.dsLk.RLock()
-	exists, err := ca.ds.Has(ctx, key)
-	ca.dsLk.RUnlock()
+	exists, err := ca.IsCached(ctx, root)
 	if err != nil || exists {
 		return err
 	}
@@ -73,7 +65,7 @@ func (ca *ShareAvailability) SharesAvailable(ctx context.Context, root *share.Ro
 	}
 
 	ca.dsLk.Lock()
-	err = ca.ds.Put(ctx, key, []byte{})
+	err = ca.ds.Put(ctx, rootKey(root), []byte{})
 	ca.dsLk.Unlock()
 	if err != nil {
 		log.Errorw("storing root of successful SharesAvailable request to disk", "err", err)
@@ -81,6 +73,27 @@ func (ca *ShareAvailability) SharesAvailable(ctx context.Context, root *share.Ro
 	return err
 }
 
+// IsCached reports whether the given Root was sampled successfully before, so it is not sampled again.
+// The minimum DAH of an empty data square is never sampled, so it is always considered cached.
+func (ca *ShareAvailability) IsCached(ctx context.Context, root *share.Root) (bool, error) {
+	if isMinRoot(root) {
+		return true, nil
+	}
+
+	ca.dsLk.RLock()
+	defer ca.dsLk.RUnlock()
+	return ca.ds.Has(ctx, rootKey(root))
+}
+
+// SampleAmount returns the amount of Shares sampled by the wrapped Availability, if it reports it.
+// It is the amount the Root is sampled with, unless it is cached, see IsCached.
+func (ca *ShareAvailability) SampleAmount(root *share.Root) int {
+	if counter, ok := ca.avail.(share.SampleCounter); ok {
+		return counter.SampleAmount(root)
+	}
+	return 0
+}
+
 func (ca *ShareAvailability) ProbabilityOfAvailability() float64 {
 	return ca.avail.ProbabilityOfAvailability()
 }
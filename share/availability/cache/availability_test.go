@@ -91,11 +91,14 @@ func TestCacheAvailability_NoDuplicateSampling(t *testing.T) {
 	// wrap dummyAvailability with a datastore
 	ds := sync.MutexWrap(datastore.NewMapDatastore())
 	ca := NewShareAvailability(&dummyAvailability{counter: 0}, ds)
+	cached, err := ca.IsCached(ctx, root)
+	require.NoError(t, err)
+	assert.False(t, cached)
 	// sample the root
-	err := ca.SharesAvailable(ctx, root)
+	err = ca.SharesAvailable(ctx, root)
 	require.NoError(t, err)
 	// ensure root was cached
-	exists, err := ca.ds.Has(ctx, rootKey(root))
+	exists, err := ca.IsCached(ctx, root)
 	require.NoError(t, err)
 	assert.True(t, exists)
 	// call sampling routine over same root again and ensure no error is returned
@@ -72,6 +72,13 @@ func (fa *ShareAvailability) SharesAvailable(ctx context.Context, root *share.Ro
 	return err
 }
 
+// SampleAmount returns the minimum amount of Shares retrieved to reconstruct the square of the given Root,
+// i.e. the amount of the original Shares.
+func (fa *ShareAvailability) SampleAmount(root *share.Root) int {
+	width := len(root.RowsRoots) / 2
+	return width * width
+}
+
 func (fa *ShareAvailability) ProbabilityOfAvailability() float64 {
 	return 1
 }
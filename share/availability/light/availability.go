@@ -111,6 +111,11 @@ func (la *ShareAvailability) SharesAvailable(ctx context.Context, dah *share.Roo
 	return nil
 }
 
+// SampleAmount returns the amount of Shares sampled from the square of the given Root.
+func (la *ShareAvailability) SampleAmount(dah *share.Root) int {
+	return sampleAmount(len(dah.RowsRoots), DefaultSampleAmount)
+}
+
 // ProbabilityOfAvailability calculates the probability that the
 // data square is available based on the amount of samples collected
 // (DefaultSampleAmount).
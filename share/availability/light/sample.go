@@ -25,6 +25,15 @@ func SampleSquare(squareWidth int, num int) ([]Sample, error) {
 	return ss.samples(), nil
 }
 
+// sampleAmount returns the amount of samples taken from the square of the given width,
+// as it may not have enough unique points for the given amount.
+func sampleAmount(squareWidth int, num int) int {
+	if num > squareWidth*squareWidth {
+		return squareWidth
+	}
+	return num
+}
+
 type squareSampler struct {
 	squareWidth int
 	smpls       map[Sample]struct{}
@@ -39,9 +48,7 @@ func newSquareSampler(squareWidth int, expectedSamples int) *squareSampler {
 
 // generateSample randomly picks unique point on a 2D spaces.
 func (ss *squareSampler) generateSample(num int) error {
-	if num > ss.squareWidth*ss.squareWidth {
-		num = ss.squareWidth
-	}
+	num = sampleAmount(ss.squareWidth, num)
 
 	done := 0
 	for done < num {